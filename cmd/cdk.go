@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/aws"
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
 	"github.com/spf13/cobra"
 )
@@ -32,13 +38,34 @@ A --profile / -p flag is available to select an AWS account:
   beta      →  AWS_PROFILE=openclaw-beta
   prod      →  AWS_PROFILE=openclaw-prod
 
+A --region flag sets AWS_REGION / AWS_DEFAULT_REGION (default: workspace aws_region).
+
+Safety:
+  --plan      run 'cdk diff' (with any stack args) and exit
+  --no-diff   skip the diff that 'cdk deploy' prints before deploying
+  deploy/destroy print the target account ID and region first; with the prod
+  profile they also ask for confirmation (--yes skips the prompt)
+
+The repo-local cdk (node_modules/.bin/cdk) is used automatically when the
+CDK repo lists aws-cdk in devDependencies, so the CLI matches the library the
+stacks were written against. --local makes this required instead of falling
+back to the global cdk on PATH.
+
 AWS_DEFAULT_OUTPUT=json is always injected. Workspace env (GITHUB_TOKEN etc.)
 is also injected so cdk synth can resolve private npm packages.
 
+The selected CDK app directory is printed before cdk runs. With no arguments,
+'spark-cli cdk' runs 'cdk list' to preview the available stacks.
+
 Examples:
+  spark-cli cdk                  # cdk list
   spark-cli cdk list
   spark-cli cdk --profile pipeline list
   spark-cli cdk -p beta deploy PipelineStack/beta/SomeStack
+  spark-cli cdk -p prod --region us-west-2 diff
+  spark-cli cdk --plan -p beta
+  spark-cli cdk deploy --no-diff SomeStack
+  spark-cli cdk --local synth
   spark-cli cdk diff
   spark-cli cdk synth`,
 	Args:               cobra.ArbitraryArgs,
@@ -46,6 +73,11 @@ Examples:
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// --- Parse --profile / -p from args manually (before forwarding to cdk) ---
 		profileShort := ""
+		region := ""
+		plan := false
+		noDiff := false
+		local := false
+		yes := false
 		var cdkArgs []string
 
 		for i := 0; i < len(args); i++ {
@@ -60,6 +92,33 @@ Examples:
 				profileShort = strings.TrimPrefix(arg, "--profile=")
 			case strings.HasPrefix(arg, "-p="):
 				profileShort = strings.TrimPrefix(arg, "-p=")
+			case arg == "--region":
+				if i+1 < len(args) {
+					region = args[i+1]
+					i++ // skip value
+				}
+			case strings.HasPrefix(arg, "--region="):
+				region = strings.TrimPrefix(arg, "--region=")
+			case arg == "--plan":
+				plan = true
+			case arg == "--no-diff":
+				noDiff = true
+			case arg == "--local":
+				local = true
+			case arg == "--yes":
+				yes = true
+			case arg == "--workspace":
+				// Flag parsing is disabled here, so handle the global flag manually
+				if i+1 < len(args) {
+					if err := workspace.SetRoot(args[i+1]); err != nil {
+						return err
+					}
+					i++ // skip value
+				}
+			case strings.HasPrefix(arg, "--workspace="):
+				if err := workspace.SetRoot(strings.TrimPrefix(arg, "--workspace=")); err != nil {
+					return err
+				}
 			default:
 				cdkArgs = append(cdkArgs, arg)
 			}
@@ -91,21 +150,41 @@ Examples:
 		}
 
 		if awsProfileEnvVal != "" {
+			if err := requireAWSProfile(awsProfileEnvVal); err != nil {
+				return err
+			}
 			fmt.Printf("Using AWS profile: %s\n", awsProfileEnvVal)
 			if profileShort == "prod" {
 				fmt.Println("⚠️  Using PROD profile — be careful!")
 			}
 		}
 
+		// --- Resolve AWS region ---
+		if region == "" {
+			region = ws.AWSRegion
+		}
+		if region != "" {
+			fmt.Printf("Using AWS region: %s\n", region)
+		}
+
 		// --- Find CDK repo dir ---
 		cdkDir, err := findCDKRepoDir(wsPath, ws)
 		if err != nil {
 			return err
 		}
+		fmt.Printf("Using CDK app: %s\n", cdkDir)
+		if others := otherCDKRepos(wsPath, ws, cdkDir); len(others) > 0 {
+			fmt.Printf("  (also has cdk.json: %s — cd into a repo to use it instead)\n", strings.Join(others, ", "))
+		}
 
-		cdkPath, err := exec.LookPath("cdk")
+		// Bare 'spark-cli cdk' previews the available stacks
+		if len(cdkArgs) == 0 && !plan {
+			cdkArgs = []string{"list"}
+		}
+
+		cdkPath, err := resolveCDKBinary(cdkDir, local)
 		if err != nil {
-			return fmt.Errorf("cdk not found in PATH — install with: npm install -g aws-cdk")
+			return err
 		}
 
 		// --- Build env ---
@@ -131,29 +210,133 @@ Examples:
 			envMap["AWS_PROFILE"] = awsProfileEnvVal
 		}
 
+		// Inject region if resolved
+		if region != "" {
+			envMap["AWS_REGION"] = region
+			envMap["AWS_DEFAULT_REGION"] = region
+		}
+
 		// Flatten env map back to slice
 		var env []string
 		for k, v := range envMap {
 			env = append(env, fmt.Sprintf("%s=%s", k, v))
 		}
 
-		c := exec.Command(cdkPath, cdkArgs...)
-		c.Dir = cdkDir
-		c.Stdin = os.Stdin
-		c.Stdout = os.Stdout
-		c.Stderr = os.Stderr
-		c.Env = env
+		// --plan: diff only, never deploy
+		if plan {
+			return runCDK(cdkPath, cdkDir, cdkDiffArgs(cdkArgs), env)
+		}
 
-		if err := c.Run(); err != nil {
-			if exit, ok := err.(*exec.ExitError); ok {
-				os.Exit(exit.ExitCode())
+		// Show what deploy is about to change, then the target account/region;
+		// prod needs confirmation, given only after the diff has been seen
+		mutating := len(cdkArgs) > 0 && (cdkArgs[0] == "deploy" || cdkArgs[0] == "destroy")
+		diffFirst := mutating && cdkArgs[0] == "deploy" && !noDiff
+		if diffFirst {
+			fmt.Println("=== cdk diff (pass --no-diff to skip) ===")
+			if err := runCDK(cdkPath, cdkDir, cdkDiffArgs(cdkArgs), env); err != nil {
+				return err
 			}
-			return err
 		}
-		return nil
+		if mutating {
+			isProd := awsProfileEnvVal == profileMap["prod"]
+			if err := confirmCDKTarget(cdkArgs[0], awsProfileEnvVal, region, isProd, yes); err != nil {
+				return err
+			}
+		}
+		if diffFirst {
+			fmt.Println("=== cdk deploy ===")
+		}
+
+		return runCDK(cdkPath, cdkDir, cdkArgs, env)
 	},
 }
 
+// confirmCDKTarget prints the account and region a deploy/destroy will hit and,
+// for prod, asks for confirmation unless --yes was given.
+func confirmCDKTarget(action, profile, region string, isProd, yes bool) error {
+	account, err := aws.AccountID(profile)
+	if err != nil {
+		account = "(unknown — " + err.Error() + ")"
+	}
+	verb := "Deploying to"
+	if action == "destroy" {
+		verb = "Destroying in"
+	}
+	fmt.Printf("%s account %s (%s) in %s\n", verb, account, orDefault(profile, "default profile"), orDefault(region, "default region"))
+
+	if !isProd || yes {
+		return nil
+	}
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return fmt.Errorf("refusing to %s to prod without confirmation — pass --yes", action)
+	}
+	fmt.Printf("⚠️  This is PROD. Continue with cdk %s? [y/N] ", action)
+	input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(input))
+	if answer != "y" && answer != "yes" {
+		return fmt.Errorf("cdk %s cancelled", action)
+	}
+	return nil
+}
+
+// runCDK runs the cdk binary in cdkDir, exiting with cdk's own exit code on failure.
+func runCDK(cdkPath, cdkDir string, cdkArgs, env []string) error {
+	c := exec.Command(cdkPath, cdkArgs...)
+	c.Dir = cdkDir
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	c.Env = env
+
+	if err := c.Run(); err != nil {
+		if exit, ok := err.(*exec.ExitError); ok {
+			os.Exit(exit.ExitCode())
+		}
+		return err
+	}
+	return nil
+}
+
+// stripCDKCommand drops a leading cdk subcommand (e.g. "deploy") so --plan can reuse stack args.
+func stripCDKCommand(args []string) []string {
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		switch args[0] {
+		case "deploy", "diff", "synth", "list", "ls", "destroy":
+			return args[1:]
+		}
+	}
+	return args
+}
+
+// cdkDeployOnlyFlags are deploy options 'cdk diff' rejects; true marks those taking a value
+var cdkDeployOnlyFlags = map[string]bool{
+	"--require-approval": true, "--outputs-file": true, "-O": true, "--notification-arns": true,
+	"--concurrency": true, "--asset-parallelism": false, "--asset-prebuild": false, "--progress": true,
+	"--method": true, "-m": true, "--change-set-name": true, "--build-exclude": true, "-E": true,
+	"--hotswap": false, "--hotswap-fallback": false, "--rollback": false, "--no-rollback": false,
+	"--watch": false, "--logs": false, "--exclusively": false, "-e": false, "--force": false, "-f": false,
+	"--import-existing-resources": false, "--ignore-no-stacks": false,
+}
+
+// cdkDiffArgs turns deploy arguments into the equivalent 'cdk diff' invocation:
+// the subcommand is replaced and deploy-only options are dropped.
+func cdkDiffArgs(args []string) []string {
+	diffArgs := []string{"diff"}
+	rest := stripCDKCommand(args)
+	for i := 0; i < len(rest); i++ {
+		name, _, hasValue := strings.Cut(rest[i], "=")
+		takesValue, deployOnly := cdkDeployOnlyFlags[name]
+		if !deployOnly {
+			diffArgs = append(diffArgs, rest[i])
+			continue
+		}
+		if takesValue && !hasValue && i+1 < len(rest) {
+			i++ // skip the option's value too
+		}
+	}
+	return diffArgs
+}
+
 // findCDKRepoDir returns the repo directory that contains cdk.json.
 // Prefers the repo containing the current working dir; otherwise the first workspace repo with cdk.json (e.g. CorePipeline).
 func findCDKRepoDir(wsPath string, ws *workspace.Workspace) (string, error) {
@@ -174,9 +357,9 @@ func findCDKRepoDir(wsPath string, ws *workspace.Workspace) (string, error) {
 		}
 	}
 
-	// Else use first workspace repo that has cdk.json (e.g. CorePipeline).
-	for _, repo := range ws.Repos {
-		repoDir := filepath.Join(wsPath, repo.Path)
+	// Else use first workspace repo (by name) that has cdk.json (e.g. CorePipeline).
+	for _, name := range sortedRepoNames(ws) {
+		repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
 		if hasCDK(repoDir) {
 			return repoDir, nil
 		}
@@ -185,6 +368,135 @@ func findCDKRepoDir(wsPath string, ws *workspace.Workspace) (string, error) {
 	return "", fmt.Errorf("no CDK app (cdk.json) found in workspace — run from CorePipeline or add cdk.json to a repo")
 }
 
+// resolveCDKBinary prefers the repo-local cdk (node_modules/.bin/cdk) when the
+// repo declares aws-cdk in devDependencies, falling back to the global cdk.
+// With local set, a missing repo-local cdk is an error.
+func resolveCDKBinary(cdkDir string, local bool) (string, error) {
+	localPath := filepath.Join(cdkDir, "node_modules", ".bin", "cdk")
+	if hasDevDependency(cdkDir, "aws-cdk") {
+		if _, err := os.Stat(localPath); err == nil {
+			fmt.Printf("Using repo-local cdk: %s\n", localPath)
+			return localPath, nil
+		}
+	}
+	if local {
+		return "", fmt.Errorf("no repo-local cdk in %s — add aws-cdk to devDependencies and run npm install", filepath.Base(cdkDir))
+	}
+
+	cdkPath, err := exec.LookPath("cdk")
+	if err != nil {
+		return "", fmt.Errorf("cdk not found in PATH — install with: npm install -g aws-cdk")
+	}
+	warnCDKVersionMismatch(cdkPath, cdkDir)
+	return cdkPath, nil
+}
+
+// warnCDKVersionMismatch warns when the cdk CLI's major version differs from
+// the aws-cdk version declared in the CDK repo's package.json.
+func warnCDKVersionMismatch(cdkPath, cdkDir string) {
+	declared := declaredCDKVersion(cdkDir)
+	if declared == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+	c := exec.CommandContext(ctx, cdkPath, "--version")
+	c.WaitDelay = time.Second // don't wait on grandchildren still holding stdout
+	out, err := c.Output()
+	if ctx.Err() != nil {
+		fmt.Printf("Warning: 'cdk --version' timed out after %s — skipping version check\n", probeTimeout)
+		return
+	}
+	if err != nil {
+		return
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return
+	}
+	cli := fields[0]
+
+	if majorVersion(cli) != majorVersion(declared) {
+		fmt.Printf("⚠️  cdk CLI %s does not match aws-cdk %s declared in %s/package.json\n", cli, declared, filepath.Base(cdkDir))
+		fmt.Println("   Use the repo-local CDK instead: npx cdk ...")
+	}
+}
+
+type packageDeps struct {
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+}
+
+// readPackageDeps parses the dependency sections of dir/package.json
+func readPackageDeps(dir string) (*packageDeps, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil, err
+	}
+	var pkg packageDeps
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, err
+	}
+	return &pkg, nil
+}
+
+func hasDevDependency(dir, name string) bool {
+	pkg, err := readPackageDeps(dir)
+	if err != nil {
+		return false
+	}
+	_, ok := pkg.DevDependencies[name]
+	return ok
+}
+
+// declaredCDKVersion returns the aws-cdk (or aws-cdk-lib) version range from package.json
+func declaredCDKVersion(dir string) string {
+	pkg, err := readPackageDeps(dir)
+	if err != nil {
+		return ""
+	}
+
+	for _, name := range []string{"aws-cdk", "aws-cdk-lib"} {
+		if v := pkg.DevDependencies[name]; v != "" {
+			return v
+		}
+		if v := pkg.Dependencies[name]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// majorVersion extracts the major component from a version or semver range (e.g. "^2.100.0" → "2")
+func majorVersion(v string) string {
+	v = strings.TrimLeft(v, "^~>=<v ")
+	if idx := strings.IndexByte(v, '.'); idx != -1 {
+		v = v[:idx]
+	}
+	return v
+}
+
+// otherCDKRepos lists workspace repos with cdk.json other than the selected one
+func otherCDKRepos(wsPath string, ws *workspace.Workspace, selected string) []string {
+	var others []string
+	for _, name := range sortedRepoNames(ws) {
+		repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+		if repoDir != selected && hasCDK(repoDir) {
+			others = append(others, name)
+		}
+	}
+	return others
+}
+
+func sortedRepoNames(ws *workspace.Workspace) []string {
+	names := make([]string, 0, len(ws.Repos))
+	for name := range ws.Repos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func hasCDK(dir string) bool {
 	_, err := os.Stat(filepath.Join(dir, cdkConfigFile))
 	return err == nil
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+)
+
+var colorMode = "auto"
+
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+)
+
+// validateColorMode rejects anything other than auto, always or never
+func validateColorMode() error {
+	switch colorMode {
+	case "auto", "always", "never":
+		return nil
+	}
+	return fmt.Errorf("invalid --color %q (use auto, always or never)", colorMode)
+}
+
+// useColor reports whether output should carry ANSI colors and symbol glyphs.
+// In auto mode that is only when stdout is a terminal and NO_COLOR is unset.
+func useColor() bool {
+	switch colorMode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	return os.Getenv("NO_COLOR") == "" && stdoutIsTerminal()
+}
+
+// stdoutIsTerminal reports whether stdout is a terminal rather than a pipe or file
+func stdoutIsTerminal() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorize wraps s in the given ANSI color when color output is enabled
+func colorize(color, s string) string {
+	if !useColor() {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+// statusIcon returns the marker for a sync status: a colored glyph on a
+// terminal, a plain ASCII tag otherwise so logs stay readable.
+func statusIcon(status string) string {
+	if !useColor() {
+		switch status {
+		case "skipped":
+			return "[skip]"
+		case "failed":
+			return "[fail]"
+		case "conflict":
+			return "[conflict]"
+		}
+		return "[ok]"
+	}
+	switch status {
+	case "skipped":
+		return colorize(ansiYellow, "⏭")
+	case "failed":
+		return colorize(ansiRed, "✗")
+	case "conflict":
+		return colorize(ansiYellow, "⚠")
+	}
+	return colorize(ansiGreen, "✓")
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var diffFetch bool
+
+var workspaceDiffCmd = &cobra.Command{
+	Use:   "diff [repo-name]",
+	Short: "Show the files each repo's current branch changes vs its sync target (--fetch)",
+	Long: `For each cloned repo, prints 'git diff --stat <remote>/<target>...HEAD': the
+files your committed local work changes since the branch forked from the
+branch sync rebases onto. Repos with nothing of their own are listed as
+clean. Uncommitted changes are not included, only flagged.
+
+Uses the remote refs from the last fetch; --fetch refreshes them first.
+
+Examples:
+  spark-cli workspace diff
+  spark-cli workspace diff BusinessAPI --fetch`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+
+		names := sortedRepoNames(ws)
+		if len(args) == 1 {
+			if _, ok := ws.Repos[args[0]]; !ok {
+				return fmt.Errorf("repo '%s' not found — run 'spark-cli list' to see repos", args[0])
+			}
+			names = args
+		}
+
+		defaults := loadDefaultBranches(wsPath)
+		defer defaults.save()
+
+		var changed []string
+		for _, name := range names {
+			repo := ws.Repos[name]
+			repoDir := filepath.Join(wsPath, repo.Path)
+			if !git.IsRepo(repoDir) {
+				continue
+			}
+
+			remote := repo.UpstreamRemote()
+			if diffFetch {
+				if err := git.FetchQuiet(repoDir, remote); err != nil {
+					fmt.Printf("%s %s: fetch %s failed: %v\n", statusIcon("failed"), name, remote, err)
+					continue
+				}
+			}
+			base := remote + "/" + getTargetBranch(defaults, ws, &repo, repoDir)
+			branch := git.GetCurrentBranch(repoDir)
+			if !git.HasCommit(repoDir, base) {
+				fmt.Printf("%s %s: %s not found — run with --fetch\n", statusIcon("skipped"), name, base)
+				continue
+			}
+
+			stat, err := git.DiffStat(repoDir, base, useColor())
+			if err != nil {
+				fmt.Printf("%s %s: %v\n", statusIcon("failed"), name, err)
+				continue
+			}
+			ahead, behind := git.AheadBehind(repoDir, "HEAD", base)
+			dirty := ""
+			if git.IsDirty(repoDir) {
+				dirty = " (+ uncommitted changes, not shown)"
+			}
+			if stat == "" {
+				fmt.Printf("%s %s: %s has no changes vs %s (↓%d)%s\n", statusIcon("synced"), name, branch, base, behind, dirty)
+				continue
+			}
+
+			changed = append(changed, name)
+			fmt.Printf("\n%s: %s vs %s (↑%d ↓%d)%s\n", name, branch, base, ahead, behind, dirty)
+			for _, line := range strings.Split(stat, "\n") {
+				fmt.Printf("  %s\n", line)
+			}
+		}
+
+		fmt.Println()
+		if len(changed) == 0 {
+			fmt.Println("No local commits differ from their sync targets")
+		} else {
+			fmt.Printf("%d repo(s) with local changes: %s\n", len(changed), strings.Join(changed, ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	workspaceDiffCmd.Flags().BoolVar(&diffFetch, "fetch", false, "Fetch each repo's sync remote before diffing")
+	workspaceCmd.AddCommand(workspaceDiffCmd)
+}
@@ -0,0 +1,377 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/aws"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/github"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envShowReveal bool
+	envExportFmt  string
+	envScopeForce bool
+)
+
+// secretKeyHints mark env keys whose values are masked unless --reveal is passed
+var secretKeyHints = []string{"TOKEN", "SECRET", "PASSWORD", "KEY", "CREDENTIAL", "PRIVATE"}
+
+var workspaceEnvCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Inspect the environment injected into run/sync commands (show, list, export, scope)",
+}
+
+var workspaceEnvScopeCmd = &cobra.Command{
+	Use:   "scope",
+	Short: "Write each repo's scoped .env containing only its env_keys",
+	Long: `For every cloned repo with "env_keys" in workspace.json, writes <repo>/.env
+containing only those keys, resolved from the workspace env and the repo's
+own env overrides. Keeps secrets out of repos that don't need them.
+
+Entries may be globs, and a leading "!" excludes keys, so a frontend-only
+repo can take just its browser-safe values:
+  "env_keys": ["NEXT_PUBLIC_*", "APP_ENV"]
+'workspace sync --env' does this automatically after refreshing .env.
+
+A repo .env that spark-cli didn't generate is left alone unless --force.
+
+Examples:
+  spark-cli workspace env scope
+  spark-cli workspace env scope --force   # replace hand-written repo .env files`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+		if n := writeScopedEnvs(wsPath, ws); n == 0 {
+			fmt.Println("No repos with env_keys set")
+		}
+		return nil
+	},
+}
+
+// writeScopedEnvs writes a scoped .env for each cloned repo declaring env_keys,
+// returning how many such repos there were (written or not).
+func writeScopedEnvs(wsPath string, ws *workspace.Workspace) int {
+	resolved := buildSyncEnv(wsPath, ws)
+	var scopedRepos int
+	for _, name := range sortedRepoNames(ws) {
+		repo := ws.Repos[name]
+		repoDir := filepath.Join(wsPath, repo.Path)
+		if len(repo.EnvKeys) == 0 || !git.IsRepo(repoDir) {
+			continue
+		}
+
+		scopedRepos++
+		scoped, missing := workspace.ScopeEnv(workspace.RepoEnv(resolved, repo), repo.EnvKeys)
+
+		if err := workspace.WriteScopedEnv(repoDir, scoped, envScopeForce); err != nil {
+			fmt.Printf("  ✗ %s: %v\n", name, err)
+			continue
+		}
+		fmt.Printf("  ✓ %s/.env (%d keys)\n", name, len(scoped))
+		if !git.IsIgnored(repoDir, ".env") {
+			fmt.Printf("    warning: .env isn't gitignored — add it to %s/.gitignore so secrets aren't committed\n", name)
+		}
+		if len(missing) > 0 {
+			fmt.Printf("    missing: %s\n", strings.Join(missing, ", "))
+		}
+	}
+	return scopedRepos
+}
+
+var workspaceEnvShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the resolved workspace env, sorted, with secrets masked",
+	Long: `Prints the environment that 'run' and 'sync' inject into commands, after
+merging (lowest to highest precedence):
+  1. .env at the workspace root
+  2. env in .spk/workspace.json (${KEY} references expanded)
+  3. GITHUB_TOKEN from 'gh auth token' (only if not already set)
+
+Values for secret-looking keys (TOKEN, SECRET, KEY, ...) are masked unless --reveal.
+
+Examples:
+  spark-cli workspace env show
+  spark-cli workspace env show --reveal`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+
+		dotEnv, _ := workspace.ReadGlobalEnv(wsPath)
+		resolved := buildSyncEnv(wsPath, ws)
+
+		keys := make([]string, 0, len(resolved))
+		for k := range resolved {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			source := "gh auth"
+			if _, ok := ws.Env[k]; ok {
+				source = "workspace.json"
+			} else if _, ok := dotEnv[k]; ok {
+				source = ".env"
+			}
+			value := resolved[k]
+			if !envShowReveal {
+				value = maskEnvValue(k, value)
+			}
+			fmt.Printf("%s=%s  # %s\n", k, value, source)
+		}
+		return nil
+	},
+}
+
+var workspaceEnvListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the SSM environments under /app/ (valid 'sync --env' values)",
+	Long: `Lists each environment under /app/ in SSM Parameter Store, with how many
+parameters it holds, using the workspace AWS profile and region (--region
+overrides). Only parameter names are read. The workspace's configured env is
+marked with *.
+
+Examples:
+  spark-cli workspace env list
+  spark-cli workspace env list --region us-west-2`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+
+		profile := ws.AWSProfile
+		if err := requireAWSProfile(profile); err != nil {
+			return err
+		}
+		region := syncRegionFor(ws)
+		if err := aws.GetCallerIdentityQuiet(profile); err != nil {
+			fmt.Println("AWS session expired, logging in...")
+			if err := aws.CheckCLI(); err != nil {
+				return err
+			}
+			if err := aws.SSOLogin(profile); err != nil {
+				return fmt.Errorf("AWS login failed: %w", err)
+			}
+		}
+
+		envs, err := github.ListSSMEnvs(profile, region)
+		if err != nil {
+			return err
+		}
+		if len(envs) == 0 {
+			fmt.Printf("No parameters under /app/ in %s\n", region)
+			return nil
+		}
+
+		names := make([]string, 0, len(envs))
+		for name := range envs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		current := orDefault(ws.SSMEnvPath, "beta")
+		fmt.Printf("SSM environments under /app/ (%s, profile %s):\n", region, orDefault(profile, "default"))
+		for _, name := range names {
+			marker := " "
+			if name == current {
+				marker = "*"
+			}
+			fmt.Printf(" %s %-20s %d parameter(s)\n", marker, name, envs[name])
+		}
+		return nil
+	},
+}
+
+var workspaceEnvExportCmd = &cobra.Command{
+	Use:   "export [repo-name]",
+	Short: "Print the resolved workspace env for eval or other tools (--format shell|dotenv|json)",
+	Long: `Prints the same resolved env as 'env show', unmasked and quoted for the
+target format, so scripts can load it non-interactively. With a repo name
+its env overrides apply too.
+
+Formats:
+  shell   export KEY='value' lines (default)
+  dotenv  KEY=value lines, single-quoted where needed (double-quoted with
+          escapes if the value contains ' or a newline)
+  json    a single JSON object
+
+Examples:
+  eval "$(spark-cli workspace env export)"
+  spark-cli workspace env export BusinessAPI --format dotenv > /tmp/api.env
+  spark-cli workspace env export --format json | jq .AWS_REGION`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch envExportFmt {
+		case "shell", "dotenv", "json":
+		default:
+			return fmt.Errorf("invalid --format %q (use shell, dotenv or json)", envExportFmt)
+		}
+
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+
+		resolved := buildSyncEnv(wsPath, ws)
+		if len(args) == 1 {
+			repo, ok := ws.Repos[args[0]]
+			if !ok {
+				return fmt.Errorf("repo '%s' not found — run 'spark-cli list' to see repos", args[0])
+			}
+			resolved = workspace.RepoEnv(resolved, repo)
+		}
+
+		if envExportFmt == "json" {
+			data, err := json.MarshalIndent(resolved, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		keys := make([]string, 0, len(resolved))
+		for k := range resolved {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if !envKeyPattern.MatchString(k) {
+				fmt.Fprintf(os.Stderr, "Warning: skipping %q — not a valid variable name\n", k)
+				continue
+			}
+			if envExportFmt == "dotenv" {
+				fmt.Printf("%s=%s\n", k, workspace.QuoteEnvValue(resolved[k]))
+			} else {
+				fmt.Printf("export %s=%s\n", k, shellQuoteArgs([]string{resolved[k]})[0])
+			}
+		}
+		return nil
+	},
+}
+
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// maskEnvValue hides values of secret-looking keys, keeping a short prefix for recognition
+func maskEnvValue(key, value string) string {
+	upper := strings.ToUpper(key)
+	for _, hint := range secretKeyHints {
+		if strings.Contains(upper, hint) {
+			if len(value) <= 8 {
+				return "****"
+			}
+			return value[:4] + "****"
+		}
+	}
+	return value
+}
+
+// envChange is one key that differs between the old and new workspace .env
+type envChange struct {
+	key      string
+	kind     string // "added", "removed" or "changed"
+	oldValue string
+	newValue string
+}
+
+// writeGlobalEnvDiff writes the SSM-derived vars to the workspace .env and
+// returns how it differs from the previous contents, including keys dropped
+// from SSM since the last refresh
+func writeGlobalEnvDiff(wsPath string, envVars map[string]string) ([]envChange, error) {
+	old, _ := workspace.ReadGlobalEnv(wsPath)
+	if err := workspace.WriteManagedEnv(wsPath, envVars); err != nil {
+		return nil, err
+	}
+	// Hand-added keys survive the write, so diff against what was written
+	updated, err := workspace.ReadGlobalEnv(wsPath)
+	if err != nil {
+		return nil, err
+	}
+	return diffEnv(old, updated), nil
+}
+
+// diffEnv lists added, removed and changed keys, sorted by key
+func diffEnv(old, updated map[string]string) []envChange {
+	var changes []envChange
+	for k, v := range updated {
+		prev, ok := old[k]
+		switch {
+		case !ok:
+			changes = append(changes, envChange{key: k, kind: "added", newValue: v})
+		case prev != v:
+			changes = append(changes, envChange{key: k, kind: "changed", oldValue: prev, newValue: v})
+		}
+	}
+	for k, v := range old {
+		if _, ok := updated[k]; !ok {
+			changes = append(changes, envChange{key: k, kind: "removed", oldValue: v})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].key < changes[j].key })
+	return changes
+}
+
+// printEnvChanges prints an env diff with secret values masked
+func printEnvChanges(changes []envChange) {
+	if len(changes) == 0 {
+		fmt.Println("  No variables changed")
+		return
+	}
+	for _, c := range changes {
+		switch c.kind {
+		case "added":
+			fmt.Printf("  + %s=%s\n", c.key, maskEnvValue(c.key, c.newValue))
+		case "removed":
+			fmt.Printf("  - %s\n", c.key)
+		case "changed":
+			fmt.Printf("  ~ %s: %s → %s\n", c.key, maskEnvValue(c.key, c.oldValue), maskEnvValue(c.key, c.newValue))
+		}
+	}
+}
+
+func init() {
+	workspaceEnvShowCmd.Flags().BoolVar(&envShowReveal, "reveal", false, "Show secret values unmasked")
+	workspaceEnvScopeCmd.Flags().BoolVar(&envScopeForce, "force", false, "Replace repo .env files that spark-cli didn't generate")
+	workspaceEnvExportCmd.Flags().StringVar(&envExportFmt, "format", "shell", "Output format: shell, dotenv or json")
+	workspaceEnvCmd.AddCommand(workspaceEnvShowCmd)
+	workspaceEnvCmd.AddCommand(workspaceEnvExportCmd)
+	workspaceEnvListCmd.Flags().StringVar(&syncRegion, "region", "", "AWS region to list (overrides workspace aws_region)")
+	workspaceEnvCmd.AddCommand(workspaceEnvListCmd)
+	workspaceEnvCmd.AddCommand(workspaceEnvScopeCmd)
+	workspaceCmd.AddCommand(workspaceEnvCmd)
+}
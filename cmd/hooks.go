@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	hooksForce bool
+	hooksCopy  bool
+)
+
+var workspaceInstallHooksCmd = &cobra.Command{
+	Use:   "install-hooks",
+	Short: "Install the workspace's shared git hooks into every repo (--force, --copy)",
+	Long: `Installs the git hooks listed under "hooks" in workspace.json into each
+cloned repo's .git/hooks, so everyone runs the same hooks:
+
+  "hooks": { "pre-commit": "hooks/pre-commit" }
+
+Script paths are relative to the workspace root. Hooks are symlinked by
+default (edits to the script apply everywhere); --copy copies them instead.
+An existing hook that isn't already ours is left alone unless --force is given.
+
+Examples:
+  spark-cli workspace install-hooks
+  spark-cli workspace install-hooks --force --copy`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+
+		if len(ws.Hooks) == 0 {
+			return fmt.Errorf("no hooks configured — add a \"hooks\" map to %s", workspace.ManifestPath(wsPath))
+		}
+
+		hookNames := make([]string, 0, len(ws.Hooks))
+		for hook, script := range ws.Hooks {
+			if _, err := os.Stat(filepath.Join(wsPath, script)); err != nil {
+				return fmt.Errorf("hook %s: script %s not found", hook, script)
+			}
+			hookNames = append(hookNames, hook)
+		}
+		sort.Strings(hookNames)
+
+		var installed, refused int
+		for _, name := range sortedRepoNames(ws) {
+			repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+			if !git.IsRepo(repoDir) {
+				continue
+			}
+			for _, hook := range hookNames {
+				script := filepath.Join(wsPath, ws.Hooks[hook])
+				target := filepath.Join(repoDir, ".git", "hooks", hook)
+
+				if _, err := os.Lstat(target); err == nil && !hooksForce {
+					if hookInstalled(target, script) {
+						continue
+					}
+					fmt.Printf("  ✗ %s: %s hook already exists (use --force to overwrite)\n", name, hook)
+					refused++
+					continue
+				}
+
+				if err := installHook(script, target); err != nil {
+					fmt.Printf("  ✗ %s: %s: %v\n", name, hook, err)
+					continue
+				}
+				fmt.Printf("  ✓ %s: %s\n", name, hook)
+				installed++
+			}
+		}
+
+		fmt.Printf("\n%d hook(s) installed", installed)
+		if refused > 0 {
+			fmt.Printf(", %d existing hook(s) left in place", refused)
+		}
+		fmt.Println()
+		return nil
+	},
+}
+
+// installHook symlinks (or with --copy, copies) script to target as an executable hook
+func installHook(script, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(target); err != nil {
+		return err
+	}
+	if !hooksCopy {
+		return os.Symlink(script, target)
+	}
+	data, err := os.ReadFile(script)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(target, data, 0755)
+}
+
+// hookInstalled reports whether target is already a symlink to, or a copy of, script
+func hookInstalled(target, script string) bool {
+	if dest, err := os.Readlink(target); err == nil {
+		return dest == script
+	}
+	have, err := os.ReadFile(target)
+	if err != nil {
+		return false
+	}
+	want, err := os.ReadFile(script)
+	return err == nil && bytes.Equal(have, want)
+}
+
+func init() {
+	workspaceInstallHooksCmd.Flags().BoolVar(&hooksForce, "force", false, "Overwrite existing hooks")
+	workspaceInstallHooksCmd.Flags().BoolVar(&hooksCopy, "copy", false, "Copy hook scripts instead of symlinking them")
+	workspaceCmd.AddCommand(workspaceInstallHooksCmd)
+}
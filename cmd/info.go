@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var workspaceInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Summarize how this workspace is configured",
+	Long: `Prints a read-only overview of the workspace configuration: location, AWS
+profile/region, SSM env path, default branch, how many repos are cloned, and
+which repos are CDK apps or Smithy models.
+
+Examples:
+  spark-cli workspace info`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+
+		var cloned, missing, cdkRepos, modelRepos []string
+		for _, name := range sortedRepoNames(ws) {
+			repo := ws.Repos[name]
+			repoDir := filepath.Join(wsPath, repo.Path)
+			if !git.IsRepo(repoDir) {
+				missing = append(missing, name)
+				continue
+			}
+			cloned = append(cloned, name)
+			if hasCDK(repoDir) {
+				cdkRepos = append(cdkRepos, name)
+			}
+			if consumers := repo.ModelConsumers(); len(consumers) > 0 {
+				modelRepos = append(modelRepos, fmt.Sprintf("%s → %s", name, strings.Join(consumers, ", ")))
+			}
+		}
+
+		vscode := "on"
+		if ws.VSCode != nil && ws.VSCode.Disabled {
+			vscode = "off"
+		}
+
+		fmt.Printf("Workspace:       %s\n", ws.Name)
+		fmt.Printf("Path:            %s\n", wsPath)
+		fmt.Printf("Created:         %s\n", orDefault(ws.CreatedAt, "(unknown)"))
+		fmt.Printf("AWS profile:     %s\n", orDefault(ws.AWSProfile, "(not set)"))
+		fmt.Printf("AWS region:      %s\n", orDefault(ws.AWSRegion, "(not set)"))
+		fmt.Printf("SSM env path:    %s\n", orDefault(ws.SSMEnvPath, "beta"))
+		if ws.AutoRefreshEnv {
+			fmt.Println("Env refresh:     on every sync (auto_refresh_env)")
+		}
+		fmt.Printf("Default branch:  %s\n", orDefault(ws.DefaultBranch, "(auto-detect per repo)"))
+		fmt.Printf("VS Code file:    %s\n", vscode)
+		fmt.Printf("Repos:           %d (%d cloned, %d not cloned)\n", len(ws.Repos), len(cloned), len(missing))
+		if len(missing) > 0 {
+			fmt.Printf("  Not cloned:    %s\n", strings.Join(missing, ", "))
+		}
+		if len(cdkRepos) > 0 {
+			fmt.Printf("  CDK apps:      %s\n", strings.Join(cdkRepos, ", "))
+		}
+		if len(modelRepos) > 0 {
+			fmt.Printf("  Models:        %s\n", strings.Join(modelRepos, "; "))
+		}
+		if len(ws.Hooks) > 0 {
+			fmt.Printf("Git hooks:       %d configured\n", len(ws.Hooks))
+		}
+		return nil
+	},
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceInfoCmd)
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// desktopNotify shows a desktop notification via osascript (macOS) or
+// notify-send (Linux). It does nothing if no notifier is available or it fails.
+func desktopNotify(title, message string) {
+	var name string
+	var args []string
+	switch runtime.GOOS {
+	case "darwin":
+		name = "osascript"
+		args = []string{"-e", "display notification " + appleScriptString(message) + " with title " + appleScriptString(title)}
+	case "linux":
+		name = "notify-send"
+		args = []string{title, message}
+	default:
+		return
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+	exec.CommandContext(ctx, name, args...).Run()
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
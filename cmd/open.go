@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var openEditor string
+
+var workspaceOpenCmd = &cobra.Command{
+	Use:   "open",
+	Short: "Open the workspace's .code-workspace file in VS Code (--editor)",
+	Long: `Generates the VS Code workspace file if it is missing and opens it with
+'code'. Falls back to 'open' (macOS) or 'xdg-open' (Linux) when 'code' is not
+on PATH. Use --editor to open it with a different command.
+
+Examples:
+  spark-cli workspace open
+  spark-cli workspace open --editor cursor`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+
+		wsFile := workspace.VSCodeWorkspacePath(wsPath)
+		if _, err := os.Stat(wsFile); os.IsNotExist(err) {
+			if err := workspace.GenerateVSCodeWorkspace(wsPath); err != nil {
+				return fmt.Errorf("failed to generate VS Code workspace: %w", err)
+			}
+			if _, err := os.Stat(wsFile); os.IsNotExist(err) {
+				return fmt.Errorf("VS Code workspace generation is disabled — run 'spark-cli workspace config set vscode on'")
+			}
+		}
+
+		editor, err := resolveEditor(openEditor)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Opening %s with %s\n", wsFile, editor)
+		c := exec.Command(editor, wsFile)
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		return c.Run()
+	},
+}
+
+// resolveEditor returns the command used to open the workspace file
+func resolveEditor(preferred string) (string, error) {
+	if preferred != "" {
+		if _, err := exec.LookPath(preferred); err != nil {
+			return "", fmt.Errorf("editor %q not found in PATH", preferred)
+		}
+		return preferred, nil
+	}
+
+	candidates := []string{"code"}
+	if runtime.GOOS == "darwin" {
+		candidates = append(candidates, "open")
+	} else {
+		candidates = append(candidates, "xdg-open")
+	}
+	for _, c := range candidates {
+		if _, err := exec.LookPath(c); err == nil {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("no editor found — install the VS Code 'code' command or pass --editor")
+}
+
+func init() {
+	workspaceOpenCmd.Flags().StringVar(&openEditor, "editor", "", "Editor command to open the workspace with (default: code)")
+	workspaceCmd.AddCommand(workspaceOpenCmd)
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var pruneYes bool
+
+var workspacePruneBranchesCmd = &cobra.Command{
+	Use:   "prune-branches [repo-name]",
+	Short: "Delete local branches already merged into the default branch (--yes)",
+	Long: `Lists local branches that are fully merged into origin/<default branch> in
+each repo and asks before deleting them. The current branch and the default
+branch are never deleted. Run 'spark-cli workspace sync' first so origin refs
+are current.
+
+Examples:
+  spark-cli workspace prune-branches              # confirm per repo
+  spark-cli workspace prune-branches --yes        # delete without asking
+  spark-cli workspace prune-branches BusinessAPI  # one repo`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+
+		var names []string
+		if len(args) == 1 {
+			if _, ok := ws.Repos[args[0]]; !ok {
+				return fmt.Errorf("repo '%s' not found in workspace", args[0])
+			}
+			names = []string{args[0]}
+		} else {
+			for name := range ws.Repos {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+		}
+
+		defaults := loadDefaultBranches(wsPath)
+		defer defaults.save()
+
+		reader := bufio.NewReader(os.Stdin)
+		var deleted int
+		for _, name := range names {
+			repo := ws.Repos[name]
+			repoDir := filepath.Join(wsPath, repo.Path)
+			if !git.IsRepo(repoDir) {
+				continue
+			}
+
+			target := getTargetBranch(defaults, ws, &repo, repoDir)
+			current := git.GetCurrentBranch(repoDir)
+
+			var candidates []string
+			for _, b := range git.MergedBranches(repoDir, "origin/"+target) {
+				if b == current || b == target {
+					continue
+				}
+				candidates = append(candidates, b)
+			}
+			if len(candidates) == 0 {
+				continue
+			}
+
+			fmt.Printf("%s: %d branch(es) merged into origin/%s\n", name, len(candidates), target)
+			for _, b := range candidates {
+				fmt.Printf("  %s\n", b)
+			}
+
+			if !pruneYes {
+				fmt.Print("Delete these branches? [y/N] ")
+				input, _ := reader.ReadString('\n')
+				answer := strings.ToLower(strings.TrimSpace(input))
+				if answer != "y" && answer != "yes" {
+					fmt.Println("  skipped")
+					continue
+				}
+			}
+
+			for _, b := range candidates {
+				if err := git.DeleteBranch(repoDir, b); err != nil {
+					fmt.Printf("  ✗ %s: %v\n", b, err)
+					continue
+				}
+				fmt.Printf("  ✓ deleted %s\n", b)
+				deleted++
+			}
+		}
+
+		if deleted > 0 {
+			fmt.Printf("\n%d branch(es) deleted\n", deleted)
+		} else {
+			fmt.Println("No merged branches deleted")
+		}
+		return nil
+	},
+}
+
+func init() {
+	workspacePruneBranchesCmd.Flags().BoolVarP(&pruneYes, "yes", "y", false, "Delete merged branches without prompting")
+	workspaceCmd.AddCommand(workspacePruneBranchesCmd)
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var relinkForce bool
+
+var workspaceRelinkCmd = &cobra.Command{
+	Use:   "relink",
+	Short: "Re-establish local package links without rebuilding",
+	Long: `Restores the linked dev state after an npm install or node_modules wipe.
+
+For every repo with model_for (and optionally a consumers list) set in
+workspace.json, if the model's Smithy SDK is already built,
+node_modules/<sdk package> in each cloned consumer is symlinked to the
+model's build output. Links that already point at the right build are left
+alone unless --force is given. CDK → Lambda symlinks are restored too.
+Nothing is rebuilt — run 'spark-cli build' in a model repo first if it
+reports "not built".
+
+Examples:
+  spark-cli workspace relink
+  spark-cli workspace relink --force   # recreate every link`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+
+		names := make([]string, 0, len(ws.Repos))
+		for name, repo := range ws.Repos {
+			if len(repo.ModelConsumers()) > 0 {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		fmt.Println("Linking model packages...")
+		var linked int
+		for _, name := range names {
+			for _, consumer := range ws.Repos[name].ModelConsumers() {
+				if relinkModel(wsPath, ws, name, consumer, relinkForce) {
+					linked++
+				}
+			}
+		}
+		if len(names) == 0 {
+			fmt.Println("  No repos with model_for/consumers set")
+		} else if linked == 0 {
+			fmt.Println("  Model packages already linked")
+		}
+
+		linkCDKDependencies(wsPath)
+		return nil
+	},
+}
+
+// relinkModel links a built model's SDK into its consumer, reporting whether a
+// link was (re)created. With force, a link that already looks right is redone.
+func relinkModel(wsPath string, ws *workspace.Workspace, model, consumer string, force bool) bool {
+	modelDir := filepath.Join(wsPath, ws.Repos[model].Path)
+	consumerRepo, ok := ws.Repos[consumer]
+	if !ok {
+		fmt.Printf("  ✗ %s: consumer repo '%s' not in workspace\n", model, consumer)
+		return false
+	}
+	consumerDir := filepath.Join(wsPath, consumerRepo.Path)
+	if _, err := os.Stat(consumerDir); os.IsNotExist(err) {
+		return false
+	}
+	if !npm.IsBuilt(modelDir) {
+		fmt.Printf("  – %s: not built — skipping %s\n", model, consumer)
+		return false
+	}
+
+	buildDir := npm.BuildOutputDir(modelDir)
+	pkg, err := npm.GetPackageName(buildDir)
+	if err != nil {
+		fmt.Printf("  ✗ %s: %v\n", model, err)
+		return false
+	}
+	if !force && npm.IsLinkedTo(consumerDir, pkg, buildDir) {
+		return false
+	}
+	if err := npm.DirectLinkWithRetry(consumerDir, pkg, buildDir); err != nil {
+		fmt.Printf("  ✗ %s → %s: %v\n", pkg, consumer, err)
+		return false
+	}
+	fmt.Printf("  🔗 %s → %s\n", pkg, consumer)
+	return true
+}
+
+func init() {
+	workspaceRelinkCmd.Flags().BoolVar(&relinkForce, "force", false, "Recreate links even when they already point at the build output")
+	workspaceCmd.AddCommand(workspaceRelinkCmd)
+}
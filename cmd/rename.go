@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var renameMove bool
+
+var workspaceRenameCmd = &cobra.Command{
+	Use:   "rename <old-name> <new-name>",
+	Short: "Rename a repo's workspace key and update references to it (--move)",
+	Long: `Renames a repo in workspace.json, e.g. after it was renamed upstream. Every
+reference to it is rewritten: other repos' dependencies, model_for and
+consumers, and group members. Recorded build state moves with it.
+
+With --move the repo directory is renamed to match and the CDK → Lambda
+symlinks and VS Code workspace file are refreshed.
+
+Examples:
+  spark-cli workspace rename BusinessAPI PartnerAPI
+  spark-cli workspace rename BusinessAPI PartnerAPI --move`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldName, newName := args[0], args[1]
+		if newName == "" || strings.ContainsAny(newName, `/\`) || newName == "." || newName == ".." {
+			return fmt.Errorf("invalid repo name '%s'", newName)
+		}
+
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+
+		repo, ok := ws.Repos[oldName]
+		if !ok {
+			return fmt.Errorf("repo '%s' not found in workspace", oldName)
+		}
+		if _, ok := ws.Repos[newName]; ok {
+			return fmt.Errorf("repo '%s' already exists in workspace", newName)
+		}
+
+		var newPath string
+		if renameMove {
+			newPath = filepath.Join(filepath.Dir(repo.Path), newName)
+			oldDir := filepath.Join(wsPath, repo.Path)
+			newDir := filepath.Join(wsPath, newPath)
+			if _, err := os.Lstat(newDir); err == nil {
+				return fmt.Errorf("%s already exists — not moving %s", newDir, oldDir)
+			}
+			if _, err := os.Stat(oldDir); err == nil {
+				if err := os.Rename(oldDir, newDir); err != nil {
+					return fmt.Errorf("failed to move %s: %w", oldDir, err)
+				}
+				fmt.Printf("Moved %s → %s\n", repo.Path, newPath)
+			}
+		}
+
+		if err := workspace.RenameRepo(wsPath, oldName, newName, newPath); err != nil {
+			return err
+		}
+		if err := workspace.RenameRepoState(wsPath, oldName, newName, repo.Path, orDefault(newPath, repo.Path)); err != nil {
+			fmt.Printf("Warning: failed to update workspace state: %v\n", err)
+		}
+		fmt.Printf("Renamed '%s' → '%s' in workspace.json\n", oldName, newName)
+
+		for _, m := range cdkLambdaMappings {
+			if m.CDK == oldName || m.Lambda == oldName {
+				fmt.Printf("Note: the built-in CDK → Lambda link %s → %s uses the old name; it no longer applies to this repo\n", m.CDK, m.Lambda)
+			}
+		}
+
+		if renameMove {
+			linkCDKDependencies(wsPath)
+			if err := workspace.GenerateVSCodeWorkspace(wsPath); err != nil {
+				fmt.Printf("Warning: failed to update VS Code workspace file: %v\n", err)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	workspaceRenameCmd.Flags().BoolVar(&renameMove, "move", false, "Also rename the repo directory to the new name")
+	workspaceCmd.AddCommand(workspaceRenameCmd)
+}
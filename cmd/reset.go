@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	resetHardTo string
+	resetForce  bool
+	resetYes    bool
+)
+
+var workspaceResetCmd = &cobra.Command{
+	Use:   "reset <repo-name>",
+	Short: "Reset a repo's current branch to match the remote (discards local commits)",
+	Long: `Recovers a branch left in a bad state (e.g. after a failed rebase) by fetching
+and running 'git reset --hard' onto the remote branch. Defaults to
+<sync_remote or origin>/<default branch>; use --hard-to for another ref.
+
+Local commits not on the target are discarded, so this always asks first
+(--yes skips the prompt). Refuses on a dirty working tree unless --force.
+The previous HEAD is printed so it can be recovered from the reflog.
+
+Examples:
+  spark-cli workspace reset BusinessAPI
+  spark-cli workspace reset BusinessAPI --hard-to origin/feature-x
+  spark-cli workspace reset BusinessAPI --force --yes   # also discard uncommitted changes`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+
+		name := args[0]
+		repo, ok := ws.Repos[name]
+		if !ok {
+			return fmt.Errorf("repo '%s' not found — run 'spark-cli list' to see repos", name)
+		}
+		repoDir := filepath.Join(wsPath, repo.Path)
+		if !git.IsRepo(repoDir) {
+			return fmt.Errorf("repo directory missing — run 'spark-cli use %s'", name)
+		}
+
+		if git.IsDirty(repoDir) && !resetForce {
+			return fmt.Errorf("%s has uncommitted changes — commit/stash them or pass --force to discard them", name)
+		}
+
+		ref := resetHardTo
+		if ref == "" {
+			defaults := loadDefaultBranches(wsPath)
+			ref = repo.UpstreamRemote() + "/" + getTargetBranch(defaults, ws, &repo, repoDir)
+			defaults.save()
+		}
+		// Only a remote-tracking ref needs a fetch; feature/x is just a local branch
+		if remote, _, ok := strings.Cut(ref, "/"); ok && slices.Contains(git.Remotes(repoDir), remote) {
+			fmt.Printf("Fetching %s...\n", remote)
+			if err := git.FetchQuiet(repoDir, remote); err != nil {
+				return fmt.Errorf("fetch %s failed: %w", remote, err)
+			}
+		}
+		if !git.HasCommit(repoDir, ref) {
+			return fmt.Errorf("%s: ref %s not found", name, ref)
+		}
+
+		branch := git.GetCurrentBranch(repoDir)
+		before, err := git.HeadSHA(repoDir)
+		if err != nil {
+			return err
+		}
+		ahead, behind := git.AheadBehind(repoDir, "HEAD", ref)
+		fmt.Printf("%s: reset %s (%.8s) to %s — discards %d local commit(s), picks up %d\n", name, branch, before, ref, ahead, behind)
+
+		if !resetYes {
+			if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+				return fmt.Errorf("refusing to reset without confirmation — pass --yes")
+			}
+			fmt.Print("Discard local commits and changes? [y/N] ")
+			input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			answer := strings.ToLower(strings.TrimSpace(input))
+			if answer != "y" && answer != "yes" {
+				fmt.Println("Cancelled")
+				return nil
+			}
+		}
+
+		if err := git.ResetHard(repoDir, ref); err != nil {
+			return fmt.Errorf("git reset --hard %s failed: %w", ref, err)
+		}
+		fmt.Printf("✓ %s now matches %s\n", branch, ref)
+		fmt.Printf("  previous HEAD was %s — undo with: git reset --hard %s\n", before, before)
+		return nil
+	},
+}
+
+func init() {
+	workspaceResetCmd.Flags().StringVar(&resetHardTo, "hard-to", "", "Ref to reset onto (default: <sync_remote or origin>/<default branch>)")
+	workspaceResetCmd.Flags().BoolVar(&resetForce, "force", false, "Also discard uncommitted changes in a dirty working tree")
+	workspaceResetCmd.Flags().BoolVarP(&resetYes, "yes", "y", false, "Reset without prompting")
+	workspaceCmd.AddCommand(workspaceResetCmd)
+}
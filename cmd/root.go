@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
@@ -13,17 +15,56 @@ var (
 	Date    = "unknown"
 )
 
+var workspaceRoot string
+
 var rootCmd = &cobra.Command{
 	Use:     "spark-cli",
 	Short:   "spark-cli — multi-repo workspace CLI",
 	Version: Version,
 	Long: `spark-cli manages multi-repo workspaces with shared environment and smart builds.
 `,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateColorMode(); err != nil {
+			return err
+		}
+		if workspaceRoot != "" {
+			return workspace.SetRoot(workspaceRoot)
+		}
+		warnNestedWorkspaces()
+		return nil
+	},
+}
+
+// warnNestedWorkspaces warns when the current directory is inside more than
+// one workspace, since commands silently resolve to the innermost one.
+func warnNestedWorkspaces() {
+	roots, err := workspace.FindAll()
+	if err != nil || len(roots) < 2 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  Nested workspaces: using %s\n", roots[0])
+	for _, outer := range roots[1:] {
+		fmt.Fprintf(os.Stderr, "   which is inside workspace %s\n", outer)
+	}
+}
+
+// exitCodeError carries a child process's exit code up to Execute so
+// spark-cli exits with the same code as the script it ran.
+type exitCodeError struct {
+	code int
+	err  error
 }
 
+func (e *exitCodeError) Error() string { return e.err.Error() }
+func (e *exitCodeError) Unwrap() error { return e.err }
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
@@ -31,6 +72,8 @@ func Execute() {
 func init() {
 	rootCmd.SetVersionTemplate(fmt.Sprintf("spark-cli %s (%s %s)\n", Version, Commit, Date))
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.PersistentFlags().StringVar(&workspaceRoot, "workspace", "", "Use the workspace at this path instead of the one containing the current directory")
+	rootCmd.PersistentFlags().StringVar(&colorMode, "color", "auto", "Colorize output: auto, always or never (auto: only when stdout is a terminal)")
 
 	// No "help" subcommand — use -h/--help only
 	rootCmd.SetHelpCommand(&cobra.Command{Hidden: true})
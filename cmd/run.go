@@ -1,14 +1,19 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
 	"github.com/spf13/cobra"
 )
@@ -20,19 +25,33 @@ const (
 	projectTypeGradle
 	projectTypeGo
 	projectTypeMake
+	projectTypePython
+	projectTypeRust
 	projectTypeUnknown
 )
 
+var (
+	runOnlyChanged bool
+	runForceLink   bool
+	runOrdered     bool
+	runKeepGoing   bool
+	runGroup       string
+	runJSON        bool
+)
+
 var runCmd = &cobra.Command{
 	Use:   "run [command] [args...]",
 	Short: "Run any command with workspace environment injected",
 	Long: `Wrapper that injects workspace environment variables into any command.
 
-If inside a repo directory, auto-detects project type and maps scripts:
+If inside a repo directory, auto-detects project type and maps scripts
+(a repo's build_command/test_command in workspace.json takes precedence):
   Node/npm:    spark-cli run <script>  →  npm run <script>
   Gradle:      spark-cli run <task>    →  ./gradlew <task>
   Go:          spark-cli run build     →  go build ./...
   Make:        spark-cli run <target>  →  make <target>
+  Poetry:      spark-cli run build     →  poetry install
+  Cargo:       spark-cli run build     →  cargo build
 
 Or pass any arbitrary command:
   spark-cli run -- aws s3 ls
@@ -48,10 +67,23 @@ Examples:
   spark-cli run              # list available scripts for current repo
   spark-cli run build        # npm run build / ./gradlew build
   spark-cli run test         # npm test / ./gradlew test
+  spark-cli run test -- --coverage src/foo.test.ts   # npm run test -- --coverage src/foo.test.ts
+  spark-cli run build --only-changed   # skip if HEAD unchanged since last build
+  spark-cli run build --force-link     # model repo: re-link SDK into consumers after build
+  spark-cli run migrate --ordered      # every repo with 'migrate', dependencies first
+  spark-cli run build --group business # every repo in the "business" group, dependencies first
+  spark-cli run build --ordered --json > results.json   # per-repo status, exit code, duration
   spark-cli run -- ls -la    # run arbitrary command with workspace env`,
 	Args:                  cobra.ArbitraryArgs,
 	DisableFlagParsing:    false,
+	SilenceUsage:          true,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		restore, err := redirectForJSON()
+		if err != nil {
+			return err
+		}
+		defer restore()
+
 		wsPath, err := workspace.Find()
 		if err != nil {
 			return err
@@ -79,6 +111,16 @@ Examples:
 			return nil
 		}
 
+		// 'run -- <cmd>' is always a raw command; 'run <script> -- <args>'
+		// forwards everything after -- to the script
+		if cmd.ArgsLenAtDash() == 0 {
+			return runRawCommand(wsPath, args, wsEnv)
+		}
+
+		if runOrdered || runGroup != "" {
+			return runScriptOrdered(wsPath, ws, args[0], args[1:], wsEnv)
+		}
+
 		// Check if inside a repo — if so, map to project-specific commands
 		repoName, _ := detectCurrentRepo(wsPath, ws)
 		if repoName != "" {
@@ -90,6 +132,38 @@ Examples:
 	},
 }
 
+// runScript runs a named script in the repo containing the current directory,
+// using the same resolution as 'spark-cli run <script>'.
+func runScript(script string, extraArgs []string) error {
+	restore, err := redirectForJSON()
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	wsPath, err := workspace.Find()
+	if err != nil {
+		return err
+	}
+
+	ws, err := workspace.Load(wsPath)
+	if err != nil {
+		return err
+	}
+
+	if runGroup != "" {
+		return runScriptOrdered(wsPath, ws, script, extraArgs, buildWorkspaceEnv(wsPath, ws))
+	}
+
+	repoName, _ := detectCurrentRepo(wsPath, ws)
+	if repoName == "" {
+		return fmt.Errorf("not inside a workspace repo — cd into a repo to run '%s'", script)
+	}
+
+	wsEnv := buildWorkspaceEnv(wsPath, ws)
+	return runRepoScript(wsPath, ws, repoName, script, extraArgs, wsEnv)
+}
+
 // buildWorkspaceEnv assembles env vars from .env, workspace.json, and gh auth
 func buildWorkspaceEnv(wsPath string, ws *workspace.Workspace) map[string]string {
 	wsEnv := make(map[string]string)
@@ -100,10 +174,8 @@ func buildWorkspaceEnv(wsPath string, ws *workspace.Workspace) map[string]string
 		wsEnv[k] = v
 	}
 
-	// Overlay workspace.json env (higher priority)
-	for k, v := range ws.Env {
-		wsEnv[k] = v
-	}
+	// Overlay workspace.json env (higher priority; ${KEY} references expanded)
+	workspace.OverlayEnv(wsEnv, ws.Env)
 
 	// Auto-resolve GITHUB_TOKEN if not set
 	wsEnv = ensureGitHubToken(wsEnv)
@@ -122,23 +194,292 @@ func runRepoScript(wsPath string, ws *workspace.Workspace, repoName, script stri
 		return fmt.Errorf("repo directory %s does not exist", repoDir)
 	}
 
+	return execRepoScript(wsPath, ws, repoName, repoDir, script, extraArgs, wsEnv)
+}
+
+// execRepoScript resolves and runs script in one repo with its env applied
+func execRepoScript(wsPath string, ws *workspace.Workspace, repoName, repoDir, script string, extraArgs []string, wsEnv map[string]string) error {
+	repo := ws.Repos[repoName]
 	projType := detectProjectType(repoDir)
+	wsEnv = workspace.RepoEnv(wsEnv, repo)
 
 	// Auto-install node_modules if missing for Node projects
 	if projType == projectTypeNode {
-		if err := ensureNodeModules(repoDir, wsEnv); err != nil {
+		if err := ensureNodeModules(repoDir, repo.InstallCommand(), wsEnv); err != nil {
 			return err
 		}
 	}
 
-	command := buildCommand(repoDir, projType, script, extraArgs)
+	command, err := resolveRepoCommand(repo, repoName, repoDir, projType, script, shellQuoteArgs(extraArgs))
+	if err != nil {
+		showAvailableScripts(repoDir, projType, repoName)
+		return err
+	}
 	if command == "" {
 		showAvailableScripts(repoDir, projType, repoName)
 		return fmt.Errorf("script '%s' not available in %s", script, repoName)
 	}
 
-	fmt.Printf("=== %s: %s ===\n", repoName, command)
-	return runShellCmdWithEnv(repoDir, command, wsEnv)
+	if script == "build" && runOnlyChanged && !buildNeeded(wsPath, repoName, repoDir) {
+		if !runJSON {
+			fmt.Printf("=== %s: no new commits since last build — skipping ===\n", repoName)
+		}
+		return nil
+	}
+
+	if !runJSON {
+		fmt.Printf("=== %s: %s ===\n", repoName, command)
+	}
+	if err := runShellCmdWithEnv(repoDir, command, wsEnv); err != nil {
+		return err
+	}
+
+	if script == "build" {
+		if sha, err := git.HeadSHA(repoDir); err == nil {
+			workspace.RecordBuild(wsPath, repoName, sha)
+		}
+		if runForceLink {
+			for _, consumer := range repo.ModelConsumers() {
+				relinkModel(wsPath, ws, repoName, consumer, true)
+			}
+		}
+	}
+	return nil
+}
+
+// runScriptOrdered runs script in every cloned repo that has it, in dependency
+// order, stopping at the first failure unless --keep-going. With --group only
+// the group's repos run (their dependencies outside the group are not run).
+func runScriptOrdered(wsPath string, ws *workspace.Workspace, script string, extraArgs []string, wsEnv map[string]string) error {
+	order, err := dependencyOrder(ws, sortedRepoNames(ws))
+	if err != nil {
+		return err
+	}
+	if runGroup != "" {
+		members, err := workspace.GroupRepos(ws, runGroup)
+		if err != nil {
+			return err
+		}
+		inGroup := make(map[string]bool, len(members))
+		for _, name := range members {
+			inGroup[name] = true
+		}
+		var filtered []string
+		for _, name := range order {
+			if inGroup[name] {
+				filtered = append(filtered, name)
+			}
+		}
+		order = filtered
+	}
+
+	var ran int
+	var failed []string
+	var results []runResult
+	for _, name := range order {
+		repo := ws.Repos[name]
+		repoDir := filepath.Join(wsPath, repo.Path)
+		if _, err := os.Stat(repoDir); os.IsNotExist(err) {
+			continue
+		}
+		command, _ := resolveRepoCommand(repo, name, repoDir, detectProjectType(repoDir), script, shellQuoteArgs(extraArgs))
+		if command == "" {
+			continue
+		}
+
+		ran++
+		result := runResult{Repo: name, Command: command, Status: "ok"}
+		if script == "build" && runOnlyChanged && !buildNeeded(wsPath, name, repoDir) {
+			result.Status = "skipped"
+		}
+		start := time.Now()
+		err := execRepoScript(wsPath, ws, name, repoDir, script, extraArgs, wsEnv)
+		result.DurationMS = time.Since(start).Milliseconds()
+		if err != nil {
+			result.Status, result.ExitCode, result.Error = "failed", 1, err.Error()
+			var exitErr *exitCodeError
+			if errors.As(err, &exitErr) {
+				result.ExitCode = exitErr.code
+			}
+		}
+		results = append(results, result)
+
+		if err != nil {
+			if !runKeepGoing {
+				if runJSON {
+					printRunResults(runJSONOut, results)
+				}
+				return fmt.Errorf("%s failed in %s (use --keep-going to continue): %w", script, name, err)
+			}
+			fmt.Printf("✗ %s: %v\n", name, err)
+			failed = append(failed, name)
+		}
+		if !runJSON {
+			fmt.Println()
+		}
+	}
+
+	if ran == 0 {
+		return fmt.Errorf("no workspace repo has a '%s' script", script)
+	}
+	if runJSON {
+		printRunResults(runJSONOut, results)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%s failed in %d of %d repo(s): %s", script, len(failed), ran, strings.Join(failed, ", "))
+	}
+	if !runJSON {
+		fmt.Printf("%s succeeded in %d repo(s)\n", script, ran)
+	}
+	return nil
+}
+
+// runJSONOut is the real stdout while --json has other output redirected to stderr
+var runJSONOut = os.Stdout
+
+// redirectForJSON validates --json and points os.Stdout at stderr so stdout
+// carries only the results array; call the returned func to undo it.
+func redirectForJSON() (func(), error) {
+	if !runJSON {
+		return func() {}, nil
+	}
+	if !runOrdered && runGroup == "" {
+		return nil, fmt.Errorf("--json applies to multi-repo runs (--ordered or --group)")
+	}
+	runJSONOut = os.Stdout
+	os.Stdout = os.Stderr
+	return func() { os.Stdout = runJSONOut }, nil
+}
+
+// runResult is one repo's entry in 'run --ordered/--group --json' output
+type runResult struct {
+	Repo       string `json:"repo"`
+	Command    string `json:"command"`
+	Status     string `json:"status"` // "ok", "failed" or "skipped" (--only-changed)
+	ExitCode   int    `json:"exit_code"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// printRunResults writes the multi-repo run results as an indented JSON array
+func printRunResults(w *os.File, results []runResult) {
+	if results == nil {
+		results = []runResult{}
+	}
+	data, _ := json.MarshalIndent(results, "", "  ")
+	fmt.Fprintln(w, string(data))
+}
+
+// dependencyOrder returns roots and their transitive dependencies (from
+// workspace.json) ordered so every repo comes after the repos it depends on.
+func dependencyOrder(ws *workspace.Workspace, roots []string) ([]string, error) {
+	var order []string
+	state := make(map[string]int) // 1 = visiting, 2 = done
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case 1:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(path, name), " → "))
+		case 2:
+			return nil
+		}
+		repo, ok := ws.Repos[name]
+		if !ok {
+			return fmt.Errorf("dependency '%s' not found in workspace", name)
+		}
+		state[name] = 1
+		for _, dep := range repo.Dependencies {
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = 2
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range roots {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// buildNeeded reports whether HEAD moved (or the tree is dirty) since the last recorded build
+func buildNeeded(wsPath, repoName, repoDir string) bool {
+	if !git.IsRepo(repoDir) || git.IsDirty(repoDir) {
+		return true
+	}
+	sha, err := git.HeadSHA(repoDir)
+	if err != nil {
+		return true
+	}
+	st, err := workspace.LoadState(wsPath)
+	if err != nil {
+		return true
+	}
+	return st.LastBuiltSHA[repoName] != sha
+}
+
+// repoCommandOverride returns the repo's configured build/test command from
+// workspace.json, or "" when the script has no override.
+func repoCommandOverride(repo workspace.RepoDef, script string, extraArgs []string) string {
+	var command string
+	switch script {
+	case "build":
+		command = repo.BuildCommand
+	case "test":
+		command = repo.TestCommand
+	}
+	if command == "" {
+		return ""
+	}
+	if len(extraArgs) > 0 {
+		command += " " + strings.Join(extraArgs, " ")
+	}
+	return command
+}
+
+// resolveRepoCommand returns the command that runs script in one repo: its
+// workspace.json override, else the project's own script, "" when it has
+// neither. extraArgs must already be shell-quoted. The command is returned
+// alongside an error when an override names an npm script that doesn't exist.
+func resolveRepoCommand(repo workspace.RepoDef, repoName, repoDir string, projType projectType, script string, extraArgs []string) (string, error) {
+	command := repoCommandOverride(repo, script, extraArgs)
+	if command == "" {
+		command = buildCommand(repoDir, projType, script, extraArgs)
+	}
+	// An override like "npm run build:all" must name a script package.json really has
+	if target := npmRunTarget(command); target != "" && projType == projectTypeNode {
+		if _, ok := getNpmScripts(repoDir)[target]; !ok && len(workspaceScriptDirs(repoDir, target)) == 0 {
+			return command, fmt.Errorf("%s_command for %s runs npm script '%s', which is not in its package.json", script, repoName, target)
+		}
+	}
+	return command, nil
+}
+
+// npmRunTarget returns the script name when command starts with "npm run <script>"
+func npmRunTarget(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) < 3 || fields[0] != "npm" || (fields[1] != "run" && fields[1] != "run-script") {
+		return ""
+	}
+	return fields[2]
+}
+
+// shellQuoteArgs single-quotes args that the shell would otherwise split or expand
+func shellQuoteArgs(args []string) []string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if a != "" && !strings.ContainsAny(a, " \t\n'\"\\$`*?[]{}()<>|&;#~!") {
+			quoted[i] = a
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+	}
+	return quoted
 }
 
 func runRawCommand(wsPath string, args []string, wsEnv map[string]string) error {
@@ -147,20 +488,20 @@ func runRawCommand(wsPath string, args []string, wsEnv map[string]string) error
 	return runShellCmdWithEnv(wsPath, command, wsEnv)
 }
 
-func ensureNodeModules(repoDir string, wsEnv map[string]string) error {
+func ensureNodeModules(repoDir, installCmd string, wsEnv map[string]string) error {
 	nodeModules := filepath.Join(repoDir, "node_modules")
 	needsInstall := false
 
 	if _, err := os.Stat(nodeModules); os.IsNotExist(err) {
-		fmt.Printf("node_modules missing — running npm install...\n")
+		fmt.Printf("node_modules missing — running %s...\n", installCmd)
 		needsInstall = true
 	} else if _, err := os.Stat(filepath.Join(nodeModules, ".package-lock.json")); os.IsNotExist(err) {
-		fmt.Printf("node_modules incomplete — running npm install...\n")
+		fmt.Printf("node_modules incomplete — running %s...\n", installCmd)
 		needsInstall = true
 	}
 
 	if needsInstall {
-		if err := runShellCmdWithEnv(repoDir, "npm install", wsEnv); err != nil {
+		if err := runShellCmdWithEnv(repoDir, installCmd, wsEnv); err != nil {
 			return fmt.Errorf("npm install failed: %w", err)
 		}
 		fmt.Println()
@@ -203,6 +544,12 @@ func detectProjectType(repoDir string) projectType {
 	if fileExistsCheck(filepath.Join(repoDir, "go.mod")) {
 		return projectTypeGo
 	}
+	if fileExistsCheck(filepath.Join(repoDir, "pyproject.toml")) || fileExistsCheck(filepath.Join(repoDir, "poetry.lock")) {
+		return projectTypePython
+	}
+	if fileExistsCheck(filepath.Join(repoDir, "Cargo.toml")) {
+		return projectTypeRust
+	}
 	if fileExistsCheck(filepath.Join(repoDir, "Makefile")) {
 		return projectTypeMake
 	}
@@ -219,20 +566,25 @@ func buildCommand(repoDir string, projType projectType, script string, extraArgs
 		return buildGoCommand(script, extraArgs)
 	case projectTypeMake:
 		return buildMakeCommand(script, extraArgs)
+	case projectTypePython:
+		return buildPoetryCommand(script, extraArgs)
+	case projectTypeRust:
+		return buildCargoCommand(script, extraArgs)
 	default:
 		return ""
 	}
 }
 
 func buildNpmCommand(repoDir, script string, extraArgs []string) string {
-	scripts := getNpmScripts(repoDir)
-	if scripts == nil {
-		return ""
-	}
-	if _, ok := scripts[script]; !ok {
+	var cmd string
+	if _, ok := getNpmScripts(repoDir)[script]; ok {
+		cmd = fmt.Sprintf("npm run %s", script)
+	} else if len(workspaceScriptDirs(repoDir, script)) > 0 {
+		// npm workspaces monorepo: run it in every package that defines it
+		cmd = fmt.Sprintf("npm run %s --workspaces --if-present", script)
+	} else {
 		return ""
 	}
-	cmd := fmt.Sprintf("npm run %s", script)
 	if len(extraArgs) > 0 {
 		cmd += " -- " + strings.Join(extraArgs, " ")
 	}
@@ -277,6 +629,32 @@ func buildMakeCommand(script string, extraArgs []string) string {
 	return "make " + strings.Join(allTargets, " ")
 }
 
+func buildPoetryCommand(script string, extraArgs []string) string {
+	var cmd string
+	switch script {
+	case "build", "install":
+		cmd = "poetry install"
+	case "test":
+		cmd = "poetry run pytest"
+	default:
+		return ""
+	}
+	if len(extraArgs) > 0 {
+		cmd += " " + strings.Join(extraArgs, " ")
+	}
+	return cmd
+}
+
+func buildCargoCommand(script string, extraArgs []string) string {
+	switch script {
+	case "build", "test", "run", "check", "fmt", "clippy":
+		allArgs := append([]string{script}, extraArgs...)
+		return "cargo " + strings.Join(allArgs, " ")
+	default:
+		return ""
+	}
+}
+
 func getNpmScripts(repoDir string) map[string]string {
 	pkgPath := filepath.Join(repoDir, "package.json")
 	data, err := os.ReadFile(pkgPath)
@@ -292,6 +670,17 @@ func getNpmScripts(repoDir string) map[string]string {
 	return pkg.Scripts
 }
 
+// workspaceScriptDirs returns the npm workspace packages (relative dirs) that define script
+func workspaceScriptDirs(repoDir, script string) []string {
+	var dirs []string
+	for _, dir := range npm.WorkspaceDirs(repoDir) {
+		if _, ok := getNpmScripts(filepath.Join(repoDir, dir))[script]; ok {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
 func showAvailableScripts(repoDir string, projType projectType, repoName string) {
 	fmt.Printf("\nAvailable scripts in %s:\n", repoName)
 	switch projType {
@@ -309,6 +698,23 @@ func showAvailableScripts(repoDir string, projType projectType, repoName string)
 				fmt.Printf("  spark-cli run %s\n", name)
 			}
 		}
+		// Scripts defined only in npm workspace packages run across all of them
+		wsOnly := make(map[string]bool)
+		for _, dir := range npm.WorkspaceDirs(repoDir) {
+			for name := range getNpmScripts(filepath.Join(repoDir, dir)) {
+				if _, atRoot := scripts[name]; !atRoot && !strings.HasPrefix(name, "pre") && !strings.HasPrefix(name, "post") {
+					wsOnly[name] = true
+				}
+			}
+		}
+		var wsNames []string
+		for name := range wsOnly {
+			wsNames = append(wsNames, name)
+		}
+		sort.Strings(wsNames)
+		for _, name := range wsNames {
+			fmt.Printf("  spark-cli run %s   (all workspace packages)\n", name)
+		}
 	case projectTypeGradle:
 		fmt.Println("  spark-cli run build")
 		fmt.Println("  spark-cli run test")
@@ -320,6 +726,14 @@ func showAvailableScripts(repoDir string, projType projectType, repoName string)
 		fmt.Println("  spark-cli run vet")
 	case projectTypeMake:
 		fmt.Println("  spark-cli run <target>")
+	case projectTypePython:
+		fmt.Println("  spark-cli run build    (poetry install)")
+		fmt.Println("  spark-cli run test     (poetry run pytest)")
+	case projectTypeRust:
+		fmt.Println("  spark-cli run build")
+		fmt.Println("  spark-cli run test")
+		fmt.Println("  spark-cli run check")
+		fmt.Println("  spark-cli run clippy")
 	default:
 		fmt.Println("  (no recognized project type)")
 	}
@@ -360,7 +774,33 @@ func runShellCmdWithEnv(dir, command string, wsEnv map[string]string) error {
 		cmd.Env = env
 	}
 
-	return cmd.Run()
+	if err := cmd.Run(); err != nil {
+		if exit, ok := err.(*exec.ExitError); ok {
+			return &exitCodeError{code: exit.ExitCode(), err: err}
+		}
+		return err
+	}
+	return nil
+}
+
+// probeTimeout bounds non-interactive helper tools (gh, cdk --version) that can
+// hang on a credential prompt or the network
+const probeTimeout = 10 * time.Second
+
+// ghAuthToken runs 'gh auth token', giving up after probeTimeout
+func ghAuthToken() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+	c := exec.CommandContext(ctx, "gh", "auth", "token")
+	c.WaitDelay = time.Second // don't wait on grandchildren still holding stdout
+	out, err := c.Output()
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("'gh auth token' timed out after %s — check your credentials/network: %w", probeTimeout, ctx.Err())
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
 }
 
 // ensureGitHubToken auto-resolves GITHUB_TOKEN from gh auth if not already set
@@ -374,12 +814,13 @@ func ensureGitHubToken(wsEnv map[string]string) map[string]string {
 		}
 	}
 
-	out, err := exec.Command("gh", "auth", "token").Output()
+	token, err := ghAuthToken()
 	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
 		return wsEnv
 	}
-
-	token := strings.TrimSpace(string(out))
 	if token == "" {
 		return wsEnv
 	}
@@ -393,5 +834,11 @@ func ensureGitHubToken(wsEnv map[string]string) map[string]string {
 }
 
 func init() {
+	runCmd.Flags().BoolVar(&runOnlyChanged, "only-changed", false, "For build: skip when the repo has no new commits since its last successful build")
+	runCmd.Flags().BoolVar(&runOrdered, "ordered", false, "Run the script in every repo that has it, in dependency order (workspace.json dependencies)")
+	runCmd.Flags().StringVar(&runGroup, "group", "", "Run the script in every repo of this workspace.json group, in dependency order")
+	runCmd.Flags().BoolVar(&runJSON, "json", false, "With --ordered/--group, print per-repo results as JSON (script output goes to stderr)")
+	runCmd.Flags().BoolVar(&runKeepGoing, "keep-going", false, "With --ordered, continue past failing repos")
+	runCmd.Flags().BoolVar(&runForceLink, "force-link", false, "For build: re-link a model's SDK into its consumers even if already linked")
 	rootCmd.AddCommand(runCmd)
 }
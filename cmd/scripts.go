@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// scriptShortcuts are common scripts exposed as top-level commands
+// (spark-cli build == spark-cli run build).
+var scriptShortcuts = []string{"build", "test", "start", "lint"}
+
+func newScriptShortcutCmd(script string) *cobra.Command {
+	return &cobra.Command{
+		Use:   fmt.Sprintf("%s [args...]", script),
+		Short: fmt.Sprintf("Shortcut for 'spark-cli run %s' in the current repo", script),
+		Long: fmt.Sprintf(`Runs the '%[1]s' script in the repo containing the current directory,
+with workspace environment injected. Same as 'spark-cli run %[1]s'.
+
+Extra arguments are passed through to the underlying script:
+  spark-cli %[1]s -- --verbose`, script),
+		Args:         cobra.ArbitraryArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runScript(script, args)
+		},
+	}
+}
+
+func init() {
+	for _, script := range scriptShortcuts {
+		c := newScriptShortcutCmd(script)
+		if script == "test" {
+			addTestAllFlags(c)
+		}
+		if script == "build" {
+			c.Flags().BoolVar(&runOnlyChanged, "only-changed", false, "Skip when the repo has no new commits since its last successful build")
+			c.Flags().BoolVar(&runForceLink, "force-link", false, "Re-link a model's SDK into its consumers even if already linked")
+			c.Flags().StringVar(&runGroup, "group", "", "Build every repo in this workspace.json group, in dependency order")
+			c.Flags().BoolVar(&runJSON, "json", false, "With --group, print per-repo results as JSON (build output goes to stderr)")
+		}
+		rootCmd.AddCommand(c)
+	}
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var shellCmd = &cobra.Command{
+	Use:   "sh [repo-name]",
+	Short: "Open an interactive shell with the workspace env loaded",
+	Long: `Spawns $SHELL with the same environment 'spark-cli run' injects: the
+workspace .env, workspace.json env, and GITHUB_TOKEN resolved from gh auth.
+Inside a repo (or when a repo is named) its env overrides apply too.
+
+The shell starts in the named repo, else the current repo, else the
+workspace root. SPARK_CLI_SHELL is set to the workspace name so prompts can
+show it; exit the shell to return.
+
+Examples:
+  spark-cli sh
+  spark-cli sh BusinessAPI`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if os.Getenv("SPARK_CLI_SHELL") != "" {
+			fmt.Println("Note: already inside a spark-cli shell — starting a nested one")
+		}
+
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+
+		wsEnv := buildWorkspaceEnv(wsPath, ws)
+		dir := wsPath
+		if len(args) == 1 {
+			repo, ok := ws.Repos[args[0]]
+			if !ok {
+				return fmt.Errorf("repo '%s' not found — run 'spark-cli list' to see repos", args[0])
+			}
+			dir = filepath.Join(wsPath, repo.Path)
+			if _, err := os.Stat(dir); err != nil {
+				return fmt.Errorf("repo directory missing — run 'spark-cli use %s'", args[0])
+			}
+			wsEnv = workspace.RepoEnv(wsEnv, repo)
+		} else if name, _ := detectCurrentRepo(wsPath, ws); name != "" {
+			wsEnv = workspace.RepoEnv(wsEnv, ws.Repos[name])
+			dir, _ = os.Getwd()
+		}
+		wsEnv["SPARK_CLI_SHELL"] = ws.Name
+
+		shell := os.Getenv("SHELL")
+		if shell == "" {
+			shell = "/bin/zsh"
+		}
+
+		env := make(map[string]string)
+		for _, e := range os.Environ() {
+			if k, v, ok := strings.Cut(e, "="); ok {
+				env[k] = v
+			}
+		}
+		for k, v := range wsEnv {
+			env[k] = v
+		}
+		var environ []string
+		for k, v := range env {
+			environ = append(environ, k+"="+v)
+		}
+
+		fmt.Printf("Starting %s in %s with %d workspace env var(s) — exit to return\n", filepath.Base(shell), dir, len(wsEnv))
+		c := exec.Command(shell)
+		c.Dir = dir
+		c.Env = environ
+		c.Stdin = os.Stdin
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		if err := c.Run(); err != nil {
+			// The shell's exit status is just its last command's; only failing to start is an error
+			if _, ok := err.(*exec.ExitError); ok {
+				return nil
+			}
+			return fmt.Errorf("failed to start %s: %w", shell, err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportOutput string
+	importForce  bool
+)
+
+// repoSnapshot records where a single repo's HEAD was at export time
+type repoSnapshot struct {
+	Branch string `json:"branch"`
+	SHA    string `json:"sha"`
+}
+
+// workspaceSnapshot is the manifest written by 'workspace export'
+type workspaceSnapshot struct {
+	Workspace  string                  `json:"workspace"`
+	ExportedAt string                  `json:"exported_at"`
+	Repos      map[string]repoSnapshot `json:"repos"`
+}
+
+var workspaceExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Snapshot each repo's current branch and commit SHA as JSON",
+	Long: `Writes a JSON manifest of repo → {branch, sha} for every cloned repo in the
+workspace. Useful for reproducing a teammate's exact state or bisecting a
+cross-repo regression.
+
+Examples:
+  spark-cli workspace export                     # print to stdout
+  spark-cli workspace export -o snapshot.json    # write to a file`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+
+		snap := workspaceSnapshot{
+			Workspace:  ws.Name,
+			ExportedAt: time.Now().UTC().Format(time.RFC3339),
+			Repos:      make(map[string]repoSnapshot),
+		}
+
+		names := make([]string, 0, len(ws.Repos))
+		for name := range ws.Repos {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+			if !git.IsRepo(repoDir) {
+				fmt.Fprintf(os.Stderr, "Skipping %s: not cloned\n", name)
+				continue
+			}
+			sha, err := git.HeadSHA(repoDir)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Skipping %s: could not resolve HEAD\n", name)
+				continue
+			}
+			snap.Repos[name] = repoSnapshot{
+				Branch: git.GetCurrentBranch(repoDir),
+				SHA:    sha,
+			}
+		}
+
+		data, err := json.MarshalIndent(snap, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal snapshot: %w", err)
+		}
+		data = append(data, '\n')
+
+		if exportOutput == "" {
+			_, err := os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(exportOutput, data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", exportOutput, err)
+		}
+		fmt.Printf("Exported %d repo(s) to %s\n", len(snap.Repos), exportOutput)
+		return nil
+	},
+}
+
+var workspaceImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Check out each repo at the commit recorded by 'workspace export'",
+	Long: `Reads a manifest written by 'workspace export' and checks out the recorded
+commit SHA in each repo (detached HEAD), fetching first if the commit is not
+available locally. Repos with uncommitted changes are skipped unless --force.
+
+Examples:
+  spark-cli workspace import snapshot.json
+  spark-cli workspace import snapshot.json --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		var snap workspaceSnapshot
+		if err := json.Unmarshal(data, &snap); err != nil {
+			return fmt.Errorf("failed to parse snapshot: %w", err)
+		}
+
+		names := make([]string, 0, len(snap.Repos))
+		for name := range snap.Repos {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var moved, failed int
+		for _, name := range names {
+			entry := snap.Repos[name]
+			short := entry.SHA
+			if len(short) > 7 {
+				short = short[:7]
+			}
+
+			repo, ok := ws.Repos[name]
+			if !ok {
+				fmt.Printf("✗ %-25s not in workspace — run 'spark-cli use %s'\n", name, name)
+				failed++
+				continue
+			}
+			repoDir := filepath.Join(wsPath, repo.Path)
+			if !git.IsRepo(repoDir) {
+				fmt.Printf("✗ %-25s not cloned\n", name)
+				failed++
+				continue
+			}
+			if git.IsDirty(repoDir) && !importForce {
+				fmt.Printf("⏭ %-25s dirty working tree (use --force)\n", name)
+				failed++
+				continue
+			}
+			if !git.HasCommit(repoDir, entry.SHA) {
+				git.FetchQuiet(repoDir, "origin")
+				if !git.HasCommit(repoDir, entry.SHA) {
+					fmt.Printf("✗ %-25s commit %s no longer exists\n", name, short)
+					failed++
+					continue
+				}
+			}
+			if err := git.CheckoutSHA(repoDir, entry.SHA); err != nil {
+				fmt.Printf("✗ %-25s checkout %s failed\n", name, short)
+				failed++
+				continue
+			}
+			fmt.Printf("✓ %-25s %s (exported from %s)\n", name, short, orDefault(entry.Branch, "-"))
+			moved++
+		}
+
+		fmt.Printf("\n%d moved, %d not moved\n", moved, failed)
+		return nil
+	},
+}
+
+func init() {
+	workspaceExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write the snapshot to this file instead of stdout")
+	workspaceImportCmd.Flags().BoolVar(&importForce, "force", false, "Check out even when a repo has uncommitted changes")
+	workspaceCmd.AddCommand(workspaceExportCmd)
+	workspaceCmd.AddCommand(workspaceImportCmd)
+}
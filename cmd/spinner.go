@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// spinner shows an animated "label (elapsed)" line while a silent step runs.
+// When stdout is not a terminal it prints "label..." once and the result after.
+type spinner struct {
+	label string
+	start time.Time
+	tty   bool
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+func startSpinner(label string) *spinner {
+	s := &spinner{label: label, start: time.Now(), tty: stdoutIsTerminal()}
+	if !s.tty {
+		fmt.Printf("  %s...", label)
+		return s
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			fmt.Printf("\r\033[K  %s %s (%s)", spinnerFrames[i%len(spinnerFrames)], s.label, s.elapsed())
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return s
+}
+
+func (s *spinner) elapsed() time.Duration {
+	return time.Since(s.start).Round(time.Second)
+}
+
+// finish replaces the spinner with ✓ or ✗ and the step's total time
+func (s *spinner) finish(err error) {
+	if !s.tty {
+		if err != nil {
+			fmt.Printf(" ✗ %v\n", err)
+		} else {
+			fmt.Printf(" ✓\n")
+		}
+		return
+	}
+	close(s.stop)
+	<-s.done
+	if err != nil {
+		fmt.Printf("\r\033[K  %s %s (%s): %v\n", statusIcon("failed"), s.label, s.elapsed(), err)
+		return
+	}
+	fmt.Printf("\r\033[K  %s %s (%s)\n", statusIcon("synced"), s.label, s.elapsed())
+}
@@ -1,30 +1,126 @@
 package cmd
 
 import (
-	"encoding/json"
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/aws"
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/github"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/npm"
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
 var (
-	syncBranch   string
-	syncNoRebase bool
-	syncEnv      string
-	syncInstall  bool
-	syncUpdate   bool
+	syncBranch           string
+	syncNoRebase         bool
+	syncEnv              string
+	syncInstall          bool
+	syncPromptInstall    bool
+	syncNotify           bool
+	syncOnlyCurrent      bool
+	syncExcludeBranches  []string
+	syncBackupRefs       bool
+	syncNoEnv            bool
+	syncAbortStale       bool
+	syncUpdate           bool
+	syncUpdateFilter     string
+	syncRegion           string
+	syncDirtyOnly        bool
+	syncInteractive      bool
+	syncPush             bool
+	syncSetUpstream      bool
+	syncJobs             int
+	syncTimeout          time.Duration
+	syncAllRemotes       bool
+	syncDivergeThreshold int
+	syncStrategy         string
+	syncNoVSCode         bool
+	syncReposFile        string
+	syncNoFetch          bool
+	syncFixSymlinks      bool
+	syncGroup            string
+	syncEnvTTL           time.Duration
+	syncDryRun           bool
+	syncUpdateConfig     bool
 )
 
+// updateWorkspaceConfig fast-forwards the git checkout holding the manifest
+// (declared by config_repo) and returns the reloaded workspace, reporting
+// repos that were added or removed upstream.
+func updateWorkspaceConfig(wsPath string, ws *workspace.Workspace) (*workspace.Workspace, error) {
+	if ws.ConfigRepo == "" {
+		return nil, fmt.Errorf("--update-config needs \"config_repo\" in workspace.json — the org/repo that versions this manifest")
+	}
+	manifest := workspace.ManifestPath(wsPath)
+	dir, err := git.TopLevel(filepath.Dir(manifest))
+	if err != nil {
+		return nil, fmt.Errorf("config_repo is set but %s is not in a git checkout — clone %s there first", manifest, ws.ConfigRepo)
+	}
+	// The checkout found may be an unrelated ancestor repo (e.g. dotfiles in $HOME) — never pull that
+	url, err := git.RemoteURL(dir, "origin")
+	if err != nil {
+		return nil, fmt.Errorf("%s has no origin remote — expected a checkout of config_repo %s", dir, ws.ConfigRepo)
+	}
+	if !git.SameRemote(url, ws.ConfigRepo) && !git.SameRemote(url, git.BuildRemoteURL(ws.ConfigRepo)) {
+		return nil, fmt.Errorf("%s tracks %s, not config_repo %s — not pulling it", dir, url, ws.ConfigRepo)
+	}
+
+	fmt.Printf("Updating workspace config in %s...\n", dir)
+	if err := git.PullFFOnly(dir); err != nil {
+		return nil, fmt.Errorf("failed to update workspace config (commit or stash local edits to the manifest): %w", err)
+	}
+	updated, err := workspace.Load(wsPath)
+	if err != nil {
+		return nil, fmt.Errorf("updated workspace config no longer loads: %w", err)
+	}
+
+	for _, name := range sortedRepoNames(updated) {
+		if _, ok := ws.Repos[name]; !ok {
+			fmt.Printf("  + %s added — run 'spark-cli use %s' to clone it\n", name, updated.Repos[name].Remote)
+		}
+	}
+	for _, name := range sortedRepoNames(ws) {
+		if _, ok := updated.Repos[name]; !ok {
+			fmt.Printf("  - %s removed from workspace config (its checkout is left in place)\n", name)
+		}
+	}
+	return updated, nil
+}
+
+// syncSummary is the one-line outcome of the last sync, e.g. "12 synced, 0 skipped, 1 failed"
+var syncSummary string
+
+// syncCtx is cancelled on --timeout expiry or Ctrl-C
+var syncCtx = context.Background()
+
+// syncProgress records the repo most recently started so a timeout or interrupt can clean it up
+var syncProgress struct {
+	mu     sync.Mutex
+	repo   string
+	dir    string
+	branch string
+}
+
+func setSyncProgress(repo, dir, branch string) {
+	syncProgress.mu.Lock()
+	defer syncProgress.mu.Unlock()
+	syncProgress.repo, syncProgress.dir, syncProgress.branch = repo, dir, branch
+}
+
 var syncCmd = &cobra.Command{
 	Use:   "sync [repo-name]",
 	Short: "Sync repos (git fetch+rebase); use --env to refresh workspace .env",
@@ -32,8 +128,33 @@ var syncCmd = &cobra.Command{
 
   spark-cli workspace sync                # sync all repos (parallel)
   spark-cli workspace sync --install      # sync + npm install where package-lock changed
+  spark-cli workspace sync --prompt-install   # ask per repo before each npm install
+  spark-cli workspace sync -i --notify    # desktop notification when done
+  spark-cli workspace sync --exclude-branches 'old-*,spike/*'   # leave matching branches alone
+  spark-cli workspace sync --only-current-branch              # don't touch other local branches
+  spark-cli workspace sync --backup-refs  # keep refs/spk-backup/<branch> at each pre-rebase SHA
   spark-cli workspace sync --env beta     # sync and refresh .env from beta
-  spark-cli workspace sync BusinessAPI    # sync one repo`,
+  spark-cli workspace sync --env prod --env-ttl 15m # reuse parameters fetched in the last 15m
+  spark-cli workspace sync -u --update-filter sra-sdk   # only bump matching packages
+  spark-cli workspace sync -u --dry-run   # table of current → latest versions, changes nothing
+  spark-cli workspace sync BusinessAPI    # sync one repo
+  spark-cli workspace sync --repos-file repos.txt   # sync repos listed one per line
+  spark-cli workspace sync --group business          # sync a group from workspace.json "groups"
+  spark-cli workspace sync --no-fetch     # rebase onto already-fetched refs (offline)
+  spark-cli workspace sync --fix-symlinks # only repair CDK → Lambda symlinks, no git
+
+Repos with "sync_remote" set (e.g. "upstream" for a fork checkout) rebase onto
+<sync_remote>/<branch> instead of origin/<branch>; that remote is fetched too.
+
+With "auto_refresh_env": true in workspace.json, every sync refreshes .env from
+ssm_env_path as if --env were passed; --no-env skips the refresh for one run.
+
+Repos with "skip_sync": true in workspace.json (or a .spk-skip file in the
+repo root) are left alone unless named explicitly.
+
+When the manifest lives in its own git checkout with "config_repo" set,
+--update-config fast-forwards that checkout and reloads it before syncing,
+so repos a teammate added are picked up.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		wsPath, err := workspace.Find()
@@ -46,40 +167,158 @@ var syncCmd = &cobra.Command{
 			return err
 		}
 
-		if len(args) == 1 {
-			if err := syncRepo(wsPath, ws, args[0]); err != nil {
-				return err
+		if syncFixSymlinks {
+			if syncUpdateConfig {
+				return fmt.Errorf("--fix-symlinks doesn't sync, so it can't be combined with --update-config")
 			}
-		} else {
-			if err := syncAllRepos(wsPath, ws); err != nil {
+			if len(args) > 0 {
+				return fmt.Errorf("--fix-symlinks repairs every CDK link — don't pass a repo name")
+			}
+			linkCDKDependencies(wsPath)
+			return nil
+		}
+
+		switch syncStrategy {
+		case "", "rebase", "merge", "pull":
+		default:
+			return fmt.Errorf("invalid --strategy %q — use rebase, merge or pull", syncStrategy)
+		}
+
+		// Ctrl-C (or --timeout) cancels this context, killing in-flight git/npm
+		// subprocesses; the in-progress repo is then restored below.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if syncTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, syncTimeout)
+			defer cancel()
+		}
+		syncCtx = ctx
+		git.SetContext(ctx)
+
+		if len(args) == 1 && syncReposFile != "" {
+			return fmt.Errorf("pass either a repo name or --repos-file, not both")
+		}
+		if syncGroup != "" && (len(args) == 1 || syncReposFile != "") {
+			return fmt.Errorf("--group can't be combined with a repo name or --repos-file")
+		}
+
+		if syncPromptInstall {
+			syncInstall = true
+		}
+		if syncNoEnv && syncEnv != "" {
+			return fmt.Errorf("--env and --no-env can't be combined")
+		}
+		if syncJobs < 1 {
+			return fmt.Errorf("--jobs must be at least 1")
+		}
+
+		if syncUpdateConfig {
+			if ws, err = updateWorkspaceConfig(wsPath, ws); err != nil {
 				return err
 			}
 		}
 
-		if syncEnv != "" {
-			if err := refreshEnvQuiet(wsPath, ws); err != nil {
-				fmt.Printf("Warning: failed to refresh .env: %v\n", err)
+		if syncDryRun {
+			if !syncUpdate {
+				return fmt.Errorf("--dry-run previews --update — pass both")
+			}
+			return previewSparkUpdates(wsPath, ws, args)
+		}
+
+		if len(args) == 1 {
+			err = syncRepo(wsPath, ws, args[0])
+		} else {
+			err = syncAllRepos(wsPath, ws)
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			notifySync("timed out after " + syncTimeout.String())
+			return fmt.Errorf("sync timed out after %s %s", syncTimeout, restoreInProgressRepo())
+		}
+		if ctx.Err() != nil {
+			fmt.Fprintf(os.Stderr, "\nsync interrupted %s\n", restoreInProgressRepo())
+			os.Exit(130)
+		}
+		if err != nil {
+			notifySync("failed: " + err.Error())
+			return err
+		}
+
+		if (syncEnv != "" || ws.AutoRefreshEnv) && !syncNoEnv {
+			changes, err := refreshEnvQuiet(wsPath, ws)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to refresh .env: %v\n", err)
 			} else {
 				fmt.Println("Refreshed workspace environment")
+				printEnvChanges(changes)
+				writeScopedEnvs(wsPath, ws)
 			}
 		}
 
-		workspace.GenerateVSCodeWorkspace(wsPath)
+		if !syncNoVSCode {
+			workspace.GenerateVSCodeWorkspace(wsPath)
+		}
+		notifySync(syncSummary)
 		return nil
 	},
 }
 
+// notifySync sends a desktop notification with the sync outcome when --notify is set
+func notifySync(message string) {
+	if syncNotify {
+		desktopNotify("spark-cli sync", orDefault(message, "done"))
+	}
+}
+
 // repoSyncResult holds the result of syncing a single repo
 type repoSyncResult struct {
 	name            string
 	branch          string
-	status          string // "synced", "skipped", "failed"
+	status          string // "synced", "skipped", "failed", "conflict"
 	message         string
 	ahead           int
 	behind          int
 	dirty           bool
 	dirtyStatus     string
 	lockfileChanged bool
+	abortAll        bool // user chose [a]bort all at an interactive conflict prompt
+}
+
+// syncResultCollector gathers per-repo results safely from concurrent goroutines
+// and hands them back in the order the repos were requested.
+type syncResultCollector struct {
+	mu      sync.Mutex
+	order   map[string]int
+	results []repoSyncResult
+}
+
+func newSyncResultCollector(names []string) *syncResultCollector {
+	order := make(map[string]int, len(names))
+	for i, name := range names {
+		order[name] = i
+	}
+	return &syncResultCollector{order: order, results: make([]repoSyncResult, 0, len(names))}
+}
+
+func (c *syncResultCollector) add(r repoSyncResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results = append(c.results, r)
+}
+
+func (c *syncResultCollector) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.results)
+}
+
+// list returns a copy of the results in request order
+func (c *syncResultCollector) list() []repoSyncResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := append([]repoSyncResult(nil), c.results...)
+	sort.SliceStable(out, func(i, j int) bool { return c.order[out[i].name] < c.order[out[j].name] })
+	return out
 }
 
 // SSM parameter suffixes to fetch
@@ -115,16 +354,9 @@ var ssmToEnvKey = map[string]string{
 	"stripePublicKey":        "STRIPE_PUBLIC_KEY",
 }
 
-func refreshEnv(wsPath string, ws *workspace.Workspace) error {
-	if err := aws.CheckCLI(); err != nil {
-		return err
-	}
-
+func refreshEnvQuiet(wsPath string, ws *workspace.Workspace) ([]envChange, error) {
 	profile := ws.AWSProfile
-	region := ws.AWSRegion
-	if region == "" {
-		region = "us-east-1"
-	}
+	region := syncRegionFor(ws)
 
 	env := syncEnv
 	if env == "" && ws.SSMEnvPath != "" {
@@ -134,62 +366,56 @@ func refreshEnv(wsPath string, ws *workspace.Workspace) error {
 		env = "beta"
 	}
 
-	fmt.Printf("Checking AWS credentials (profile: %s)...\n", orDefault(profile, "default"))
-	if err := aws.GetCallerIdentity(profile); err != nil {
-		fmt.Println("AWS session expired, logging in...")
-		if err := aws.SSOLogin(profile); err != nil {
-			return fmt.Errorf("AWS login failed: %w", err)
+	ssmVars, _, cached := workspace.CachedSSMParams(wsPath, profile, region, env, syncEnvTTL)
+	if !cached {
+		if err := requireAWSProfile(profile); err != nil {
+			return nil, err
+		}
+		if err := aws.GetCallerIdentityQuiet(profile); err != nil {
+			if err := aws.CheckCLI(); err != nil {
+				return nil, err
+			}
+			if err := aws.SSOLogin(profile); err != nil {
+				return nil, fmt.Errorf("AWS login failed: %w", err)
+			}
 		}
-	}
 
-	fmt.Printf("Fetching environment from /app/%s/... (%d parameters)\n", env, len(ssmParamSuffixes))
-	ssmVars, err := github.FetchMultipleFromSSM(profile, env, region, ssmParamSuffixes)
-	if err != nil {
-		return fmt.Errorf("failed to fetch parameters: %w", err)
+		var err error
+		ssmVars, err = github.FetchMultipleFromSSM(profile, env, region, ssmParamSuffixes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch parameters: %w", err)
+		}
+		if syncEnvTTL > 0 {
+			workspace.CacheSSMParams(wsPath, profile, region, env, ssmVars)
+		}
 	}
 
 	envVars := mapSSMToEnv(ssmVars, region, env, ws)
-
-	if err := workspace.WriteGlobalEnv(wsPath, envVars); err != nil {
-		return err
-	}
-
-	fmt.Printf("Updated %s (%d variables)\n", workspace.GlobalEnvPath(wsPath), len(envVars))
-	return nil
+	return writeGlobalEnvDiff(wsPath, envVars)
 }
 
-func refreshEnvQuiet(wsPath string, ws *workspace.Workspace) error {
-	if err := aws.CheckCLI(); err != nil {
-		return err
-	}
-
-	profile := ws.AWSProfile
-	region := ws.AWSRegion
-	if region == "" {
-		region = "us-east-1"
-	}
-
-	env := syncEnv
-	if env == "" && ws.SSMEnvPath != "" {
-		env = ws.SSMEnvPath
+// requireAWSProfile fails fast with setup guidance when profile isn't in the
+// AWS config, instead of a cryptic STS error followed by a failed SSO login
+func requireAWSProfile(profile string) error {
+	if aws.ProfileExists(profile) {
+		return nil
 	}
-	if env == "" {
-		env = "beta"
+	msg := fmt.Sprintf("AWS profile '%s' not configured — run 'aws configure sso --profile %s'", profile, profile)
+	if known := aws.GetSSOProfiles(); len(known) > 0 {
+		msg += fmt.Sprintf(" or pick one of: %s ('spark-cli workspace config set aws_profile <name>')", strings.Join(known, ", "))
 	}
+	return errors.New(msg)
+}
 
-	if err := aws.GetCallerIdentityQuiet(profile); err != nil {
-		if err := aws.SSOLogin(profile); err != nil {
-			return fmt.Errorf("AWS login failed: %w", err)
-		}
+// syncRegionFor returns the SSM region: --region, then workspace config, then us-east-1
+func syncRegionFor(ws *workspace.Workspace) string {
+	if syncRegion != "" {
+		return syncRegion
 	}
-
-	ssmVars, err := github.FetchMultipleFromSSM(profile, env, region, ssmParamSuffixes)
-	if err != nil {
-		return fmt.Errorf("failed to fetch parameters: %w", err)
+	if ws.AWSRegion != "" {
+		return ws.AWSRegion
 	}
-
-	envVars := mapSSMToEnv(ssmVars, region, env, ws)
-	return workspace.WriteGlobalEnv(wsPath, envVars)
+	return "us-east-1"
 }
 
 func mapSSMToEnv(ssmVars map[string]string, region, env string, ws *workspace.Workspace) map[string]string {
@@ -247,13 +473,12 @@ func mapSSMToEnv(ssmVars map[string]string, region, env string, ws *workspace.Wo
 		envVars["NEXT_PUBLIC_APP_ENV"] = env
 	}
 
-	for k, v := range ws.Env {
-		envVars[k] = v
-	}
+	// workspace.json env may reference SSM-derived values, e.g. API_URL=https://${API_HOST}/v1
+	workspace.OverlayEnv(envVars, ws.Env)
 	return envVars
 }
 
-func getTargetBranch(ws *workspace.Workspace, repo *workspace.RepoDef, repoDir string) string {
+func getTargetBranch(defaults *defaultBranches, ws *workspace.Workspace, repo *workspace.RepoDef, repoDir string) string {
 	if syncBranch != "" {
 		return syncBranch
 	}
@@ -261,9 +486,85 @@ func getTargetBranch(ws *workspace.Workspace, repo *workspace.RepoDef, repoDir s
 		return repo.DefaultBranch
 	}
 	if ws.DefaultBranch != "" {
+		if repo != nil {
+			if actual := git.RemoteHeadBranch(repoDir, repo.UpstreamRemote()); actual != "" && actual != ws.DefaultBranch {
+				warnDefaultBranchMismatch(repoDir, ws.DefaultBranch, repo.UpstreamRemote(), actual)
+			}
+		}
 		return ws.DefaultBranch
 	}
-	return git.GetDefaultBranch(repoDir)
+	if repo == nil {
+		return git.GetDefaultBranch(repoDir)
+	}
+	return defaults.lookup(repo.Path, repoDir, repo.UpstreamRemote())
+}
+
+var (
+	branchMismatchMu     sync.Mutex
+	branchMismatchWarned = make(map[string]bool)
+)
+
+// warnDefaultBranchMismatch notes, once per repo, that the workspace-wide
+// default_branch being used isn't the branch the repo's remote HEAD points at
+func warnDefaultBranchMismatch(repoDir, configured, remote, actual string) {
+	branchMismatchMu.Lock()
+	defer branchMismatchMu.Unlock()
+	if branchMismatchWarned[repoDir] {
+		return
+	}
+	branchMismatchWarned[repoDir] = true
+	fmt.Printf("⚠️  %s: using workspace default_branch %s, but %s/HEAD is %s (set default_branch on the repo to override)\n",
+		filepath.Base(repoDir), configured, remote, actual)
+}
+
+// defaultBranches is the default-branch cache from workspace state, loaded
+// once per command; save writes back any branches that were re-detected.
+type defaultBranches struct {
+	wsPath  string
+	st      *workspace.State
+	changed bool
+}
+
+func loadDefaultBranches(wsPath string) *defaultBranches {
+	st, err := workspace.LoadState(wsPath)
+	if err != nil {
+		st = &workspace.State{}
+	}
+	if st.DefaultBranches == nil {
+		st.DefaultBranches = make(map[string]string)
+	}
+	return &defaultBranches{wsPath: wsPath, st: st}
+}
+
+// lookup returns the cached default branch for a repo path, re-detecting it
+// from remote's HEAD only when the cached branch no longer resolves there.
+func (d *defaultBranches) lookup(repoPath, repoDir, remote string) string {
+	if b := d.st.DefaultBranches[repoPath]; b != "" && git.RemoteBranchExists(repoDir, remote, b) {
+		return b
+	}
+	b := git.RemoteHeadBranch(repoDir, remote)
+	if b == "" {
+		b = git.GetDefaultBranch(repoDir)
+	}
+	if d.st.DefaultBranches[repoPath] != b {
+		d.st.DefaultBranches[repoPath] = b
+		d.changed = true
+	}
+	return b
+}
+
+// save persists re-detected branches, re-reading state so concurrent updates
+// to its other fields (e.g. build SHAs recorded meanwhile) aren't lost
+func (d *defaultBranches) save() {
+	if !d.changed {
+		return
+	}
+	st, err := workspace.LoadState(d.wsPath)
+	if err != nil {
+		return
+	}
+	st.DefaultBranches = d.st.DefaultBranches
+	workspace.SaveState(d.wsPath, st)
 }
 
 // cdkLambdaMappings defines which Lambda repo each CDK repo needs symlinked inside it.
@@ -298,15 +599,16 @@ func linkCDKDependencies(wsPath string) {
 		// Check if symlink already exists and is valid
 		if info, err := os.Lstat(symlinkPath); err == nil {
 			if info.Mode()&os.ModeSymlink != 0 {
-				// Verify it resolves correctly
-				if _, err := os.Stat(symlinkPath); err == nil {
+				// Verify it resolves to this workspace's Lambda repo
+				if npm.SameResolvedPath(symlinkPath, lambdaDir) {
 					// Valid symlink — skip silently
 					continue
 				}
-				// Broken symlink — remove and recreate
+				// Broken or stale symlink — remove and recreate
 				os.Remove(symlinkPath)
 			} else {
-				// Something else exists there (real dir/file) — skip
+				// Something else exists there (real dir/file) — leave it, but say so
+				fmt.Printf("  ⚠ %s/%s exists and is not a symlink — leaving it\n", m.CDK, m.Lambda)
 				continue
 			}
 		}
@@ -336,10 +638,15 @@ func syncRepo(wsPath string, ws *workspace.Workspace, name string) error {
 		return fmt.Errorf("repo directory missing — run 'spark-cli use %s'", name)
 	}
 
-	result := syncRepoFull(wsPath, ws, name, repo, repoDir)
+	warnRemoteMismatch(name, repo, repoDir)
+
+	defaults := loadDefaultBranches(wsPath)
+	result := syncRepoFull(wsPath, defaults, ws, name, repo, repoDir)
+	defaults.save()
 	printResult(result)
+	syncSummary = fmt.Sprintf("%s %s", name, result.status)
 
-	if syncInstall && result.lockfileChanged {
+	if syncInstall && result.lockfileChanged && confirmInstall(name) {
 		installRepo(wsPath, ws, name, repoDir)
 	}
 
@@ -354,43 +661,135 @@ func syncRepo(wsPath string, ws *workspace.Workspace, name string) error {
 	return nil
 }
 
-func syncAllRepos(wsPath string, ws *workspace.Workspace) error {
-	if len(ws.Repos) == 0 {
-		fmt.Println("No repos in workspace — run 'spark-cli use <repo>' to add one")
-		return nil
+// warnRemoteMismatch warns when a cloned repo's origin doesn't match its
+// workspace.json remote (e.g. a fork or the wrong repo cloned into the folder).
+func warnRemoteMismatch(name string, repo workspace.RepoDef, repoDir string) {
+	if repo.Remote == "" || !git.IsRepo(repoDir) {
+		return
 	}
-
-	allNames := make([]string, 0, len(ws.Repos))
-	for name := range ws.Repos {
-		allNames = append(allNames, name)
+	origin, err := git.RemoteURL(repoDir, "origin")
+	if err != nil {
+		return
 	}
-	sort.Strings(allNames)
+	expected := git.BuildRemoteURL(repo.Remote)
+	if !git.SameRemote(origin, expected) {
+		fmt.Printf("⚠️  %s: origin is %s but workspace expects %s\n", name, origin, expected)
+	}
+}
 
-	// Phase 1: parallel fetch all repos
+// fetchRepos fetches origin (and each repo's sync_remote) for the named repos in parallel
+func fetchRepos(wsPath string, ws *workspace.Workspace, names []string) {
 	fmt.Println("Fetching all repos...")
+	if hasHTTPSRemote(wsPath, ws, names) {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			token = buildSyncEnv(wsPath, ws)["GITHUB_TOKEN"]
+		}
+		git.SetCredentialToken(token)
+	}
 	var wg sync.WaitGroup
-	for _, name := range allNames {
+	for _, name := range names {
 		repo := ws.Repos[name]
 		repoDir := filepath.Join(wsPath, repo.Path)
 		if _, err := os.Stat(repoDir); os.IsNotExist(err) {
 			continue
 		}
+		if workspace.SkipsSync(repo, repoDir) {
+			continue
+		}
 		wg.Add(1)
-		go func(dir string) {
+		go func(dir, syncRemote string) {
 			defer wg.Done()
+			if syncAllRemotes {
+				git.FetchAllRemotes(dir)
+				return
+			}
 			git.FetchQuiet(dir, "origin")
-		}(repoDir)
+			if syncRemote != "origin" {
+				git.FetchQuiet(dir, syncRemote)
+			}
+		}(repoDir, repo.UpstreamRemote())
 	}
 	wg.Wait()
+}
+
+// hasHTTPSRemote reports whether any of the named repos fetches its origin or
+// sync remote over HTTPS, where git may need a credential
+func hasHTTPSRemote(wsPath string, ws *workspace.Workspace, names []string) bool {
+	for _, name := range names {
+		repo := ws.Repos[name]
+		repoDir := filepath.Join(wsPath, repo.Path)
+		for _, remote := range []string{"origin", repo.UpstreamRemote()} {
+			if url, err := git.RemoteURL(repoDir, remote); err == nil && strings.HasPrefix(url, "https://") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func syncAllRepos(wsPath string, ws *workspace.Workspace) error {
+	if len(ws.Repos) == 0 {
+		fmt.Println("No repos in workspace — run 'spark-cli use <repo>' to add one")
+		return nil
+	}
+
+	allNames := make([]string, 0, len(ws.Repos))
+	for name := range ws.Repos {
+		allNames = append(allNames, name)
+	}
+	sort.Strings(allNames)
+
+	if syncReposFile != "" {
+		names, err := readReposFile(syncReposFile, ws)
+		if err != nil {
+			return err
+		}
+		allNames = names
+	}
+	if syncGroup != "" {
+		names, err := workspace.GroupRepos(ws, syncGroup)
+		if err != nil {
+			return err
+		}
+		allNames = names
+	}
+
+	var excludedClean int
+	if syncDirtyOnly {
+		allNames, excludedClean = filterDirtyRepos(wsPath, ws, allNames)
+		if len(allNames) == 0 {
+			fmt.Printf("No repos with local changes (%d clean repo(s) excluded by --dirty-only)\n", excludedClean)
+			return nil
+		}
+	}
+
+	for _, name := range allNames {
+		repo := ws.Repos[name]
+		warnRemoteMismatch(name, repo, filepath.Join(wsPath, repo.Path))
+	}
+
+	timings := &syncTimings{start: time.Now()}
+
+	// Phase 1: parallel fetch all repos
+	if syncNoFetch {
+		fmt.Println("Skipping fetch (--no-fetch) — using existing remote refs")
+	} else {
+		done := timings.begin("fetch")
+		fetchRepos(wsPath, ws, allNames)
+		done()
+	}
 
 	// Phase 2: rebase all branches sequentially (safe, needs working tree)
-	results := make([]repoSyncResult, 0, len(allNames))
+	doneRebase := timings.begin("rebase")
+	collector := newSyncResultCollector(allNames)
+	defaults := loadDefaultBranches(wsPath)
 	for _, name := range allNames {
 		repo := ws.Repos[name]
 		repoDir := filepath.Join(wsPath, repo.Path)
 
 		if _, err := os.Stat(repoDir); os.IsNotExist(err) {
-			results = append(results, repoSyncResult{
+			collector.add(repoSyncResult{
 				name:    name,
 				status:  "skipped",
 				message: "not cloned",
@@ -398,50 +797,80 @@ func syncAllRepos(wsPath string, ws *workspace.Workspace) error {
 			continue
 		}
 
-		result := syncRepoFull(wsPath, ws, name, repo, repoDir)
-		results = append(results, result)
+		if workspace.SkipsSync(repo, repoDir) {
+			collector.add(repoSyncResult{
+				name:    name,
+				status:  "skipped",
+				message: "skipped (config)",
+			})
+			continue
+		}
+
+		if syncCtx.Err() != nil {
+			break
+		}
+
+		result := syncRepoFull(wsPath, defaults, ws, name, repo, repoDir)
+		collector.add(result)
+		if result.abortAll {
+			for _, rest := range allNames[collector.len():] {
+				collector.add(repoSyncResult{name: rest, status: "skipped", message: "sync aborted"})
+			}
+			break
+		}
 	}
+	results := collector.list()
+	defaults.save()
+	doneRebase()
 
 	// Phase 3: print status table
 	fmt.Println()
-	printStatusTable(results)
+	syncSummary = printStatusTable(results)
+	if excludedClean > 0 {
+		fmt.Printf("%d clean repo(s) excluded by --dirty-only\n", excludedClean)
+	}
 
 	// Phase 4: npm install where package-lock changed
 	if syncInstall {
 		fmt.Println("\nInstalling dependencies where package-lock.json changed...")
-		wsEnv := buildSyncEnv(wsPath, ws)
-		var installed int
+		doneInstall := timings.begin("install")
+		var pending []string
 		for _, r := range results {
 			if !r.lockfileChanged {
 				continue
 			}
-			repo := ws.Repos[r.name]
-			repoDir := filepath.Join(wsPath, repo.Path)
+			repoDir := filepath.Join(wsPath, ws.Repos[r.name].Path)
 			if _, err := os.Stat(filepath.Join(repoDir, "package.json")); os.IsNotExist(err) {
 				continue
 			}
-			fmt.Printf("  npm install %s...", r.name)
-			if err := runSyncCmd(repoDir, "npm install", wsEnv); err != nil {
-				fmt.Printf(" ✗ %v\n", err)
-			} else {
-				fmt.Printf(" ✓\n")
-				installed++
+			if confirmInstall(r.name) {
+				pending = append(pending, r.name)
 			}
 		}
-		if installed > 0 {
+		installed, failed := installRepos(wsPath, ws, pending)
+		switch {
+		case len(failed) > 0:
+			fmt.Printf("%d repo(s) installed, %d failed: %s\n", installed, len(failed), strings.Join(failed, ", "))
+			syncSummary += fmt.Sprintf(", %d install(s) failed", len(failed))
+		case installed > 0:
 			fmt.Printf("%d repo(s) installed\n", installed)
-		} else {
+		default:
 			fmt.Println("No repos needed npm install")
 		}
+		doneInstall()
 	}
 
 	if syncUpdate {
 		fmt.Println("\nUpdating @spark-rewards packages to latest...")
+		doneUpdate := timings.begin("update")
 		wsEnv := buildSyncEnv(wsPath, ws)
 		var updated int
 		for _, name := range allNames {
 			repo := ws.Repos[name]
 			repoDir := filepath.Join(wsPath, repo.Path)
+			if workspace.SkipsSync(repo, repoDir) {
+				continue
+			}
 
 			// Skip if no package.json
 			if _, err := os.Stat(filepath.Join(repoDir, "package.json")); os.IsNotExist(err) {
@@ -449,19 +878,19 @@ func syncAllRepos(wsPath string, ws *workspace.Workspace) error {
 			}
 
 			// Find @spark-rewards/* dependencies
-			pkgs := findSparkPackages(repoDir)
+			pkgs := filterPackages(findSparkPackages(repoDir), syncUpdateFilter)
 			if len(pkgs) == 0 {
 				continue
 			}
 
 			// Update each package to latest
+			setSyncProgress(name, "", "")
 			for _, pkg := range pkgs {
-				fmt.Printf("  %s: %s@latest...", name, pkg)
-				cmd := fmt.Sprintf("npm install %s@latest --save", pkg)
-				if err := runSyncCmd(repoDir, cmd, wsEnv); err != nil {
-					fmt.Printf(" ✗\n")
-				} else {
-					fmt.Printf(" ✓\n")
+				cmd := sparkUpdateCommand(repo, repoDir, pkg)
+				spin := startSpinner(fmt.Sprintf("%s: %s@latest", name, pkg))
+				err := runSyncCmd(repoDir, cmd, workspace.RepoEnv(wsEnv, repo))
+				spin.finish(err)
+				if err == nil {
 					updated++
 				}
 			}
@@ -471,32 +900,149 @@ func syncAllRepos(wsPath string, ws *workspace.Workspace) error {
 		} else {
 			fmt.Println("All @spark-rewards packages already up to date")
 		}
+		doneUpdate()
 	}
 
 	// Phase 5: link CDK dependencies
 	linkCDKDependencies(wsPath)
 
+	fmt.Printf("\n%s\n", timings.summary())
 	return nil
 }
 
+// installRepos runs each repo's install command, up to --jobs at once (npm's
+// cache is safe to share), and returns how many succeeded and which failed.
+func installRepos(wsPath string, ws *workspace.Workspace, names []string) (int, []string) {
+	wsEnv := buildSyncEnv(wsPath, ws)
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		installed int
+		failed    []string
+	)
+	sem := make(chan struct{}, syncJobs)
+	for _, name := range names {
+		if syncCtx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			repo := ws.Repos[name]
+			repoDir := filepath.Join(wsPath, repo.Path)
+			setSyncProgress(name, "", "")
+			wasClean := !git.IsDirty(repoDir)
+			label := fmt.Sprintf("%s %s", repo.InstallCommand(), name)
+
+			// One install at a time gets the live spinner; parallel ones report as they finish
+			var spin *spinner
+			if syncJobs == 1 || len(names) == 1 {
+				spin = startSpinner(label)
+			}
+			started := time.Now()
+			err := runSyncCmd(repoDir, repo.InstallCommand(), workspace.RepoEnv(wsEnv, repo))
+
+			mu.Lock()
+			defer mu.Unlock()
+			if spin != nil {
+				spin.finish(err)
+			} else if err != nil {
+				fmt.Printf("  %s %s (%s): %v\n", statusIcon("failed"), label, roundDuration(time.Since(started)), err)
+			} else {
+				fmt.Printf("  %s %s (%s)\n", statusIcon("synced"), label, roundDuration(time.Since(started)))
+			}
+			if err != nil {
+				failed = append(failed, name)
+			} else {
+				installed++
+			}
+			if wasClean {
+				restoreRewrittenLockfile(name, repoDir)
+			}
+		}(name)
+	}
+	wg.Wait()
+	sort.Strings(failed)
+	return installed, failed
+}
+
+// syncTimings records how long each sync phase took, in the order they ran
+type syncTimings struct {
+	start  time.Time
+	phases []syncPhase
+}
+
+type syncPhase struct {
+	name     string
+	duration time.Duration
+}
+
+// begin starts timing a phase; call the returned func when it finishes
+func (t *syncTimings) begin(name string) func() {
+	started := time.Now()
+	return func() {
+		t.phases = append(t.phases, syncPhase{name: name, duration: time.Since(started)})
+	}
+}
+
+// summary formats e.g. "Took 1m4s: fetch 12s, rebase 4s, install 45s"
+func (t *syncTimings) summary() string {
+	parts := make([]string, 0, len(t.phases))
+	for _, p := range t.phases {
+		parts = append(parts, fmt.Sprintf("%s %s", p.name, roundDuration(p.duration)))
+	}
+	return fmt.Sprintf("Took %s: %s", roundDuration(time.Since(t.start)), strings.Join(parts, ", "))
+}
+
+// roundDuration keeps sub-second phases readable (e.g. 340ms) and rounds longer ones to 0.1s
+func roundDuration(d time.Duration) time.Duration {
+	if d < time.Second {
+		return d.Round(10 * time.Millisecond)
+	}
+	return d.Round(100 * time.Millisecond)
+}
+
 // syncRepoFull fetches, rebases all local branches onto main, and returns status
-func syncRepoFull(wsPath string, ws *workspace.Workspace, name string, repo workspace.RepoDef, repoDir string) repoSyncResult {
+func syncRepoFull(wsPath string, defaults *defaultBranches, ws *workspace.Workspace, name string, repo workspace.RepoDef, repoDir string) repoSyncResult {
+	// Never start a rebase on top of one left behind by an earlier sync or by hand
+	if git.IsRebaseInProgress(repoDir) {
+		if !syncAbortStale {
+			return repoSyncResult{
+				name:    name,
+				branch:  "(rebasing)",
+				status:  "skipped",
+				message: "rebase in progress — resolve or abort first (or --abort-stale)",
+			}
+		}
+		if err := git.RebaseAbortQuiet(repoDir); err != nil {
+			return repoSyncResult{name: name, branch: "(rebasing)", status: "failed", message: "could not abort stale rebase: " + err.Error()}
+		}
+		fmt.Printf("  %s: aborted stale rebase\n", name)
+	}
+
 	currentBranch := git.GetCurrentBranch(repoDir)
-	targetBranch := getTargetBranch(ws, &repo, repoDir)
-	upstream := fmt.Sprintf("origin/%s", targetBranch)
+	targetBranch := getTargetBranch(defaults, ws, &repo, repoDir)
+	upstream := fmt.Sprintf("%s/%s", repo.UpstreamRemote(), targetBranch)
 
 	result := repoSyncResult{
 		name:   name,
 		branch: currentBranch,
 	}
+	setSyncProgress(name, repoDir, currentBranch)
 
 	// Get ahead/behind for current branch vs origin/main
 	result.ahead, result.behind = git.AheadBehind(repoDir, currentBranch, upstream)
 
 	// Check dirty
 	if git.IsDirty(repoDir) {
+		if syncDirtyOnly {
+			return syncAutostash(wsPath, defaults, ws, name, repo, repoDir)
+		}
 		result.dirty = true
-		status, err := git.StatusShortColor(repoDir)
+		status, err := git.StatusShortColor(repoDir, useColor())
 		if err != nil || status == "" {
 			status, _ = git.Status(repoDir)
 		}
@@ -506,27 +1052,76 @@ func syncRepoFull(wsPath string, ws *workspace.Workspace, name string, repo work
 		return result
 	}
 
-	if syncNoRebase {
-		if err := git.Pull(repoDir); err != nil {
+	strategy := syncStrategyFor(repo)
+
+	if strategy == "pull" {
+		// Fast-forward only, so the result doesn't depend on each machine's pull.rebase config
+		if err := git.PullFFOnly(repoDir); err != nil {
 			result.status = "failed"
 			result.message = err.Error()
+			if result.ahead > 0 && result.behind > 0 {
+				result.message = "can't fast-forward — branch has diverged (use --strategy rebase or merge)"
+			}
 			return result
 		}
 		result.status = "synced"
 		return result
 	}
 
+	if strategy == "merge" {
+		lockBefore := fileHash(filepath.Join(repoDir, "package-lock.json"))
+		if err := git.MergeQuiet(repoDir, upstream); err != nil {
+			git.MergeAbortQuiet(repoDir)
+			result.status = "failed"
+			result.message = fmt.Sprintf("merge %s into %s failed", upstream, currentBranch)
+			return result
+		}
+		result.lockfileChanged = lockBefore != fileHash(filepath.Join(repoDir, "package-lock.json"))
+		result.ahead, result.behind = git.AheadBehind(repoDir, currentBranch, upstream)
+		result.status = "synced"
+		result.message = "merged " + upstream
+		if syncPush {
+			if msg := pushIfAhead(repoDir, currentBranch); msg != "" {
+				result.message += ", " + msg
+			}
+		}
+		return result
+	}
+
+	// Heavily diverged branches make for painful multi-conflict rebases — ask first
+	if syncDivergeThreshold > 0 && result.ahead >= syncDivergeThreshold && result.behind >= syncDivergeThreshold {
+		if !confirmDivergedRebase(name, currentBranch, upstream, result.ahead, result.behind) {
+			result.status = "skipped"
+			result.message = fmt.Sprintf("diverged from %s (↑%d ↓%d) — rebase or merge manually", upstream, result.ahead, result.behind)
+			return result
+		}
+	}
+
 	// Record package-lock hash before rebase
 	lockBefore := fileHash(filepath.Join(repoDir, "package-lock.json"))
 
-	// Get all local branches
-	branches := git.ListLocalBranches(repoDir)
+	// Get all local branches (other branches are left alone with --only-current-branch)
+	var branches []string
+	if !syncOnlyCurrent {
+		branches = git.ListLocalBranches(repoDir)
+	}
 
 	// Rebase current branch first
+	currentSHA := snapshotBranch(repoDir, currentBranch)
 	if err := git.RebaseQuiet(repoDir, upstream); err != nil {
+		if syncInteractive {
+			switch promptRebaseConflict(name, repoDir, currentBranch, upstream) {
+			case "resolve":
+				result.status = "conflict"
+				result.message = fmt.Sprintf("left mid-rebase onto %s", upstream)
+				return result
+			case "abort":
+				result.abortAll = true
+			}
+		}
 		git.RebaseAbortQuiet(repoDir)
 		result.status = "failed"
-		result.message = fmt.Sprintf("rebase %s onto %s failed", currentBranch, upstream)
+		result.message = fmt.Sprintf("rebase %s onto %s failed%s", currentBranch, upstream, verifyBranchRestored(repoDir, currentBranch, currentSHA))
 		return result
 	}
 
@@ -534,16 +1129,17 @@ func syncRepoFull(wsPath string, ws *workspace.Workspace, name string, repo work
 	var rebasedOthers []string
 	var failedOthers []string
 	for _, branch := range branches {
-		if branch == currentBranch || branch == targetBranch {
+		if branch == currentBranch || branch == targetBranch || branchExcluded(branch) {
 			continue
 		}
 		// Checkout, rebase, come back
+		sha := snapshotBranch(repoDir, branch)
 		if err := git.CheckoutQuiet(repoDir, branch); err != nil {
 			continue
 		}
 		if err := git.RebaseQuiet(repoDir, upstream); err != nil {
 			git.RebaseAbortQuiet(repoDir)
-			failedOthers = append(failedOthers, branch)
+			failedOthers = append(failedOthers, branch+verifyBranchRestored(repoDir, branch, sha))
 		} else {
 			rebasedOthers = append(rebasedOthers, branch)
 		}
@@ -563,6 +1159,20 @@ func syncRepoFull(wsPath string, ws *workspace.Workspace, name string, repo work
 	if len(rebasedOthers) > 0 {
 		result.message = fmt.Sprintf("+%d branches rebased", len(rebasedOthers))
 	}
+	if msg := fixMissingUpstreams(repoDir, append([]string{currentBranch}, rebasedOthers...)); msg != "" {
+		if result.message != "" {
+			result.message += ", "
+		}
+		result.message += msg
+	}
+	if syncPush {
+		if msg := pushIfAhead(repoDir, currentBranch); msg != "" {
+			if result.message != "" {
+				result.message += ", "
+			}
+			result.message += msg
+		}
+	}
 	if len(failedOthers) > 0 {
 		if result.message != "" {
 			result.message += ", "
@@ -573,14 +1183,194 @@ func syncRepoFull(wsPath string, ws *workspace.Workspace, name string, repo work
 	return result
 }
 
-func printResult(r repoSyncResult) {
-	icon := "✓"
-	if r.status == "skipped" {
-		icon = "⏭"
-	} else if r.status == "failed" {
-		icon = "✗"
+// readReposFile reads repo names (one per line; blank lines and # comments
+// ignored) and checks each is defined in the workspace.
+func readReposFile(path string, ws *workspace.Workspace) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repos file: %w", err)
+	}
+
+	var names, unknown []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		name := strings.TrimSpace(line)
+		if name == "" || strings.HasPrefix(name, "#") || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if _, ok := ws.Repos[name]; !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		names = append(names, name)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("repos not in workspace: %s", strings.Join(unknown, ", "))
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no repos listed in %s", path)
+	}
+	return names, nil
+}
+
+// syncAutostash syncs a dirty repo for --dirty-only: its local changes are
+// stashed, the repo synced as if clean, and the stash popped back.
+func syncAutostash(wsPath string, defaults *defaultBranches, ws *workspace.Workspace, name string, repo workspace.RepoDef, repoDir string) repoSyncResult {
+	if err := git.StashQuiet(repoDir); err != nil {
+		return repoSyncResult{name: name, branch: git.GetCurrentBranch(repoDir), status: "failed", message: "could not stash local changes: " + err.Error()}
+	}
+	result := syncRepoFull(wsPath, defaults, ws, name, repo, repoDir)
+	result.dirty = true
+	if git.IsRebaseInProgress(repoDir) {
+		result.message += " — local changes are stashed; 'git stash pop' once the rebase is done"
+		return result
+	}
+	if err := git.StashPopQuiet(repoDir); err != nil {
+		result.status = "conflict"
+		result.message += " — restoring stashed local changes conflicted; resolve, then 'git stash drop'"
+	}
+	return result
+}
+
+// filterDirtyRepos keeps only cloned repos with uncommitted changes and returns how many were excluded
+func filterDirtyRepos(wsPath string, ws *workspace.Workspace, names []string) ([]string, int) {
+	var dirty []string
+	for _, name := range names {
+		repoDir := filepath.Join(wsPath, ws.Repos[name].Path)
+		if git.IsRepo(repoDir) && git.IsDirty(repoDir) {
+			dirty = append(dirty, name)
+		}
+	}
+	return dirty, len(names) - len(dirty)
+}
+
+// snapshotBranch returns a branch's SHA before sync rewrites it and, with
+// --backup-refs, saves it as refs/spk-backup/<branch>. Returns "" if unknown.
+func snapshotBranch(repoDir, branch string) string {
+	sha, err := git.BranchSHA(repoDir, branch)
+	if err != nil {
+		return ""
+	}
+	if syncBackupRefs {
+		git.CreateBackupRef(repoDir, branch, sha)
+	}
+	return sha
+}
+
+// verifyBranchRestored checks a branch is back at its pre-rebase SHA after an
+// aborted rebase. Returns a note with the SHA to recover from, e.g. " (was abc1234)".
+func verifyBranchRestored(repoDir, branch, before string) string {
+	if before == "" {
+		return ""
+	}
+	after, err := git.BranchSHA(repoDir, branch)
+	if err == nil && after == before {
+		return fmt.Sprintf(" (was %.7s, unchanged)", before)
+	}
+	return fmt.Sprintf(" (⚠ moved — was %.7s; recover with: git branch -f %s %s)", before, branch, before)
+}
+
+// branchExcluded reports whether a branch matches an --exclude-branches glob
+// (e.g. "wip/*", "old-*") and so is skipped by the other-branches rebase
+func branchExcluded(branch string) bool {
+	for _, pattern := range syncExcludeBranches {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// syncStrategyFor resolves how a repo is updated: --strategy, then --no-rebase,
+// then the repo's sync_strategy in workspace.json, defaulting to rebase.
+func syncStrategyFor(repo workspace.RepoDef) string {
+	if syncStrategy != "" {
+		return syncStrategy
+	}
+	if syncNoRebase {
+		return "pull"
+	}
+	if repo.SyncStrategy != "" {
+		return repo.SyncStrategy
+	}
+	return "rebase"
+}
+
+// pushIfAhead pushes branch with --force-with-lease when it is ahead of its
+// upstream; branches without an upstream are left alone. Returns a status note.
+func pushIfAhead(repoDir, branch string) string {
+	upstream := git.Upstream(repoDir, branch)
+	if upstream == "" {
+		return ""
+	}
+	ahead, _ := git.AheadBehind(repoDir, branch, upstream)
+	if ahead == 0 {
+		return ""
 	}
-	line := fmt.Sprintf("%s %-25s %-20s", icon, r.name, r.branch)
+	if err := git.PushForceWithLease(repoDir, branch); err != nil {
+		return fmt.Sprintf("push %s failed", branch)
+	}
+	return fmt.Sprintf("pushed %s to %s (+%d)", branch, upstream, ahead)
+}
+
+// fixMissingUpstreams finds branches with no upstream and, with --set-upstream,
+// points each at origin/<branch> so ahead/behind and --push have a target.
+// Returns a short note for the sync result, or "".
+func fixMissingUpstreams(repoDir string, branches []string) string {
+	var missing []string
+	for _, branch := range branches {
+		if branch == "" || branch == "HEAD" {
+			continue
+		}
+		if git.HasUpstream(repoDir, branch) {
+			continue
+		}
+		missing = append(missing, branch)
+	}
+	if len(missing) == 0 {
+		return ""
+	}
+	if !syncSetUpstream {
+		return fmt.Sprintf("%d branch(es) without upstream: %s (--set-upstream)", len(missing), strings.Join(missing, ", "))
+	}
+	var set []string
+	for _, branch := range missing {
+		if err := git.SetUpstream(repoDir, branch, "origin/"+branch); err == nil {
+			set = append(set, branch)
+		}
+	}
+	if len(set) == 0 {
+		return "failed to set upstreams"
+	}
+	return fmt.Sprintf("upstream set for %s", strings.Join(set, ", "))
+}
+
+// restoreInProgressRepo aborts any rebase left behind in the repo that was in
+// progress when sync was cancelled, checks its original branch back out, and
+// returns a short description of where sync stopped.
+func restoreInProgressRepo() string {
+	git.SetContext(context.Background())
+
+	syncProgress.mu.Lock()
+	repo, dir, branch := syncProgress.repo, syncProgress.dir, syncProgress.branch
+	syncProgress.mu.Unlock()
+
+	if repo == "" {
+		return "while fetching repos"
+	}
+	if dir != "" {
+		git.RebaseAbortQuiet(dir)
+		if branch != "" {
+			git.CheckoutQuiet(dir, branch)
+		}
+		return fmt.Sprintf("while processing %s (restored to %s)", repo, branch)
+	}
+	return fmt.Sprintf("while processing %s", repo)
+}
+
+func printResult(r repoSyncResult) {
+	line := fmt.Sprintf("%s %-25s %-20s", statusIcon(r.status), r.name, r.branch)
 	if r.ahead > 0 || r.behind > 0 {
 		line += fmt.Sprintf(" ↑%d ↓%d", r.ahead, r.behind)
 	}
@@ -596,8 +1386,10 @@ func printResult(r repoSyncResult) {
 	fmt.Println(line)
 }
 
-func printStatusTable(results []repoSyncResult) {
+// printStatusTable prints one line per repo plus totals, returning the totals line
+func printStatusTable(results []repoSyncResult) string {
 	var synced, skipped, failed int
+	var conflicts []string
 	for _, r := range results {
 		printResult(r)
 		switch r.status {
@@ -607,9 +1399,77 @@ func printStatusTable(results []repoSyncResult) {
 			skipped++
 		case "failed":
 			failed++
+		case "conflict":
+			conflicts = append(conflicts, r.name)
+		}
+	}
+	summary := fmt.Sprintf("%d synced, %d skipped, %d failed", synced, skipped, failed)
+	fmt.Printf("\n%s\n", summary)
+	if len(conflicts) > 0 {
+		fmt.Printf("\n%d repo(s) left mid-rebase — resolve conflicts, then 'git rebase --continue' (or --abort):\n", len(conflicts))
+		for _, name := range conflicts {
+			fmt.Printf("  • %s\n", name)
+		}
+		summary += fmt.Sprintf(", %d with conflicts", len(conflicts))
+	}
+	return summary
+}
+
+// confirmDivergedRebase asks whether to rebase a branch that has diverged past
+// the threshold. Without a terminal on stdin it answers yes with a warning, so
+// scripts and CI rebase as they did before the threshold existed.
+func confirmDivergedRebase(name, branch, upstream string, ahead, behind int) bool {
+	fmt.Printf("\n⚠ %s: %s has diverged from %s (↑%d ↓%d)\n", name, branch, upstream, ahead, behind)
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		fmt.Println("  No terminal to confirm — rebasing anyway (--diverge-threshold 0 silences this)")
+		return true
+	}
+	fmt.Print("  Rebase anyway? [y/N] ")
+	input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(input))
+	return answer == "y" || answer == "yes"
+}
+
+// confirmInstall asks before npm install in a repo when --prompt-install is
+// set; otherwise it always says yes. Without a terminal on stdin it answers no.
+func confirmInstall(name string) bool {
+	if !syncPromptInstall {
+		return true
+	}
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		fmt.Printf("  – %s: skipped (no terminal for --prompt-install)\n", name)
+		return false
+	}
+	fmt.Printf("  npm install in %s? [y/N] ", name)
+	input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(input))
+	return answer == "y" || answer == "yes"
+}
+
+// promptRebaseConflict shows the conflicting files and asks how to proceed.
+// Returns "resolve" (leave mid-rebase), "skip" (abort this repo) or "abort" (abort all).
+func promptRebaseConflict(name, repoDir, branch, upstream string) string {
+	fmt.Printf("\n✗ %s: rebase of %s onto %s hit conflicts\n", name, branch, upstream)
+	for _, f := range git.ConflictFiles(repoDir) {
+		fmt.Printf("    %s\n", f)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print("  [r]esolve manually, [s]kip this repo, [a]bort all? ")
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			return "skip"
+		}
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "r", "resolve":
+			return "resolve"
+		case "s", "skip", "":
+			return "skip"
+		case "a", "abort":
+			return "abort"
 		}
 	}
-	fmt.Printf("\n%d synced, %d skipped, %d failed\n", synced, skipped, failed)
 }
 
 func fileHash(path string) string {
@@ -625,13 +1485,28 @@ func installRepo(wsPath string, ws *workspace.Workspace, name, repoDir string) {
 	if _, err := os.Stat(filepath.Join(repoDir, "package.json")); os.IsNotExist(err) {
 		return
 	}
-	wsEnv := buildSyncEnv(wsPath, ws)
-	fmt.Printf("  npm install %s...", name)
-	if err := runSyncCmd(repoDir, "npm install", wsEnv); err != nil {
-		fmt.Printf(" ✗ %v\n", err)
-	} else {
-		fmt.Printf(" ✓\n")
+	repo := ws.Repos[name]
+	wsEnv := workspace.RepoEnv(buildSyncEnv(wsPath, ws), repo)
+	wasClean := !git.IsDirty(repoDir)
+	spin := startSpinner(fmt.Sprintf("%s %s", repo.InstallCommand(), name))
+	spin.finish(runSyncCmd(repoDir, repo.InstallCommand(), wsEnv))
+	if wasClean {
+		restoreRewrittenLockfile(name, repoDir)
+	}
+}
+
+// restoreRewrittenLockfile undoes an install that left package-lock.json as
+// the repo's only change, so sync's own install doesn't block the next sync.
+func restoreRewrittenLockfile(name, repoDir string) {
+	changed := git.ChangedFiles(repoDir)
+	if len(changed) != 1 || changed[0] != "package-lock.json" {
+		return
 	}
+	if err := git.RestoreFile(repoDir, "package-lock.json"); err != nil {
+		fmt.Printf("    ⚠ %s: npm install modified package-lock.json (restore failed: %v)\n", name, err)
+		return
+	}
+	fmt.Printf("    %s: npm install rewrote package-lock.json — restored it\n", name)
 }
 
 func buildSyncEnv(wsPath string, ws *workspace.Workspace) map[string]string {
@@ -640,21 +1515,30 @@ func buildSyncEnv(wsPath string, ws *workspace.Workspace) map[string]string {
 	for k, v := range dotEnv {
 		wsEnv[k] = v
 	}
-	for k, v := range ws.Env {
-		wsEnv[k] = v
-	}
+	workspace.OverlayEnv(wsEnv, ws.Env)
 	return ensureGitHubTokenSync(wsEnv)
 }
 
 func runSyncCmd(dir, command string, wsEnv map[string]string) error {
+	return newSyncCmd(dir, command, wsEnv).Run()
+}
+
+// newSyncCmd builds a login-shell command for sync: cancelled with syncCtx,
+// output discarded unless the caller sets it, and wsEnv overlaid on os env.
+func newSyncCmd(dir, command string, wsEnv map[string]string) *exec.Cmd {
 	shell := os.Getenv("SHELL")
 	if shell == "" {
 		shell = "/bin/zsh"
 	}
-	cmd := exec.Command(shell, "-l", "-c", command)
+	cmd := exec.CommandContext(syncCtx, shell, "-l", "-c", command)
 	cmd.Dir = dir
 	cmd.Stdout = nil
 	cmd.Stderr = nil
+	// Own process group so a timeout kills the shell and everything it spawned (npm, node)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
 
 	if len(wsEnv) > 0 {
 		envMap := make(map[string]string)
@@ -672,40 +1556,63 @@ func runSyncCmd(dir, command string, wsEnv map[string]string) error {
 		}
 		cmd.Env = env
 	}
-	return cmd.Run()
+	return cmd
 }
 
-// findSparkPackages reads package.json and returns all @spark-rewards/* dependency names
+// findSparkPackages returns all @spark-rewards/* dependency names declared in
+// package.json — across every package for an npm workspaces monorepo
 func findSparkPackages(repoDir string) []string {
-	pkgPath := filepath.Join(repoDir, "package.json")
-	data, err := os.ReadFile(pkgPath)
-	if err != nil {
-		return nil
+	seen := make(map[string]bool)
+	var result []string
+	for _, dir := range npmPackageDirs(repoDir) {
+		for name := range declaredDependencies(filepath.Join(repoDir, dir)) {
+			if strings.HasPrefix(name, "@spark-rewards/") && !seen[name] {
+				seen[name] = true
+				result = append(result, name)
+			}
+		}
 	}
+	sort.Strings(result)
+	return result
+}
 
-	var pkg struct {
-		Dependencies    map[string]string `json:"dependencies"`
-		DevDependencies map[string]string `json:"devDependencies"`
+// npmPackageDirs returns "." plus, for an npm workspaces monorepo, each workspace package dir
+func npmPackageDirs(repoDir string) []string {
+	return append([]string{"."}, npm.WorkspaceDirs(repoDir)...)
+}
+
+// sparkUpdateCommand returns the npm install that bumps pkg to latest. In a
+// workspaces monorepo it runs at the root, targeting (-w) each package that
+// declares pkg, plus the root itself if it does too.
+func sparkUpdateCommand(repo workspace.RepoDef, repoDir, pkg string) string {
+	cmd := fmt.Sprintf("%s %s@latest --save", repo.InstallCommand(), pkg)
+	var targets []string
+	for _, dir := range npm.WorkspaceDirs(repoDir) {
+		if _, ok := declaredDependencies(filepath.Join(repoDir, dir))[pkg]; ok {
+			targets = append(targets, "-w "+filepath.ToSlash(dir))
+		}
 	}
-	if err := json.Unmarshal(data, &pkg); err != nil {
-		return nil
+	if len(targets) == 0 {
+		return cmd
+	}
+	cmd += " " + strings.Join(targets, " ")
+	if _, ok := declaredDependencies(repoDir)[pkg]; ok {
+		cmd += " --include-workspace-root"
 	}
+	return cmd
+}
 
-	seen := make(map[string]bool)
-	var result []string
-	for name := range pkg.Dependencies {
-		if strings.HasPrefix(name, "@spark-rewards/") && !seen[name] {
-			seen[name] = true
-			result = append(result, name)
-		}
+// filterPackages keeps packages whose full name contains pattern (all when pattern is empty)
+func filterPackages(pkgs []string, pattern string) []string {
+	if pattern == "" {
+		return pkgs
 	}
-	for name := range pkg.DevDependencies {
-		if strings.HasPrefix(name, "@spark-rewards/") && !seen[name] {
-			seen[name] = true
-			result = append(result, name)
+	var result []string
+	for _, pkg := range pkgs {
+		if strings.Contains(pkg, pattern) {
+			result = append(result, pkg)
 		}
 	}
-	sort.Strings(result)
 	return result
 }
 
@@ -718,11 +1625,13 @@ func ensureGitHubTokenSync(wsEnv map[string]string) map[string]string {
 			return wsEnv
 		}
 	}
-	out, err := exec.Command("gh", "auth", "token").Output()
+	token, err := ghAuthToken()
 	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
 		return wsEnv
 	}
-	token := strings.TrimSpace(string(out))
 	if token != "" {
 		if wsEnv == nil {
 			wsEnv = make(map[string]string)
@@ -734,9 +1643,35 @@ func ensureGitHubTokenSync(wsEnv map[string]string) map[string]string {
 
 func init() {
 	syncCmd.Flags().StringVar(&syncBranch, "branch", "", "Target branch (default: main)")
-	syncCmd.Flags().BoolVar(&syncNoRebase, "no-rebase", false, "Use git pull instead of rebase")
+	syncCmd.Flags().BoolVar(&syncNoRebase, "no-rebase", false, "Use git pull --ff-only instead of rebase")
+	syncCmd.Flags().StringVar(&syncStrategy, "strategy", "", "How to update the current branch: rebase, merge or pull (fast-forward only) (default: repo sync_strategy, else rebase)")
 	syncCmd.Flags().StringVar(&syncEnv, "env", "", "Refresh .env from this SSM environment (e.g. beta, prod)")
+	syncCmd.Flags().BoolVar(&syncNoEnv, "no-env", false, "Skip the .env refresh even when auto_refresh_env is on")
+	syncCmd.Flags().BoolVar(&syncNoVSCode, "no-vscode", false, "Don't regenerate the .code-workspace file")
+	syncCmd.Flags().DurationVar(&syncEnvTTL, "env-ttl", 0, "Reuse SSM parameters cached for the same env within this long, e.g. 15m (default: always fetch, no cache)")
+	syncCmd.Flags().StringVar(&syncRegion, "region", "", "AWS region for the SSM env refresh (overrides workspace aws_region)")
 	syncCmd.Flags().BoolVarP(&syncInstall, "install", "i", false, "Run npm install on repos where package-lock.json changed")
+	syncCmd.Flags().IntVarP(&syncJobs, "jobs", "j", 4, "Run up to this many npm installs at once in the --install phase")
+	syncCmd.Flags().BoolVar(&syncOnlyCurrent, "only-current-branch", false, "Rebase only the checked-out branch, not every local branch")
+	syncCmd.Flags().StringSliceVar(&syncExcludeBranches, "exclude-branches", nil, "Don't rebase local branches matching these globs (comma-separated, e.g. 'wip/*,old-*')")
+	syncCmd.Flags().BoolVar(&syncAbortStale, "abort-stale", false, "Abort rebases left in progress (instead of skipping those repos) before syncing")
+	syncCmd.Flags().BoolVar(&syncBackupRefs, "backup-refs", false, "Before rebasing, save each branch's SHA as refs/spk-backup/<branch> for recovery")
+	syncCmd.Flags().BoolVar(&syncNotify, "notify", false, "Show a desktop notification when sync finishes (macOS osascript / Linux notify-send)")
+	syncCmd.Flags().BoolVar(&syncPromptInstall, "prompt-install", false, "Like --install, but ask before npm install in each repo")
 	syncCmd.Flags().BoolVarP(&syncUpdate, "update", "u", false, "Update @spark-rewards/* packages to latest in all repos")
+	syncCmd.Flags().IntVar(&syncDivergeThreshold, "diverge-threshold", 20, "Ask before rebasing a branch at least this many commits both ahead and behind (0 disables)")
+	syncCmd.Flags().BoolVar(&syncAllRemotes, "all-remotes", false, "Fetch every remote (git fetch --all), not just origin")
+	syncCmd.Flags().DurationVar(&syncTimeout, "timeout", 0, "Abort the whole sync after this long (e.g. 5m); 0 means no limit")
+	syncCmd.Flags().BoolVar(&syncPush, "push", false, "After rebasing, push the current branch (--force-with-lease) when ahead of its upstream")
+	syncCmd.Flags().BoolVar(&syncSetUpstream, "set-upstream", false, "Make rebased branches with no upstream track origin/<branch>")
+	syncCmd.Flags().BoolVar(&syncInteractive, "interactive", false, "Prompt on rebase conflicts instead of aborting automatically")
+	syncCmd.Flags().BoolVar(&syncDirtyOnly, "dirty-only", false, "Only sync repos with uncommitted changes, stashing them around the sync")
+	syncCmd.Flags().BoolVar(&syncNoFetch, "no-fetch", false, "Skip fetching and rebase onto the already-fetched remote refs")
+	syncCmd.Flags().StringVar(&syncReposFile, "repos-file", "", "Only sync the repos listed in this file (one name per line)")
+	syncCmd.Flags().StringVar(&syncGroup, "group", "", "Only sync the repos in this workspace.json group")
+	syncCmd.Flags().BoolVar(&syncFixSymlinks, "fix-symlinks", false, "Only validate and repair CDK → Lambda symlinks, skipping all git operations")
+	syncCmd.Flags().BoolVar(&syncUpdateConfig, "update-config", false, "First pull the config_repo checkout holding workspace.json and reload it")
+	syncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "With --update, only print each repo's @spark-rewards packages with current → latest versions")
+	syncCmd.Flags().StringVar(&syncUpdateFilter, "update-filter", "", "With --update, only update packages whose name contains this pattern")
 	workspaceCmd.AddCommand(syncCmd)
 }
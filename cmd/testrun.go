@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	testAll   bool
+	testJUnit string
+)
+
+// testResult holds the outcome of running the test script in one repo
+type testResult struct {
+	name     string
+	command  string
+	status   string // "passed", "failed", "skipped"
+	message  string
+	duration time.Duration
+}
+
+// addTestAllFlags adds --all/--junit to the 'test' shortcut command.
+func addTestAllFlags(c *cobra.Command) {
+	c.Long += `
+
+Use --all to run the test script in every workspace repo and print an
+aggregated summary; --junit writes the results as JUnit XML for CI:
+  spark-cli test --all
+  spark-cli test --all --junit results.xml`
+	c.Flags().BoolVar(&testAll, "all", false, "Run tests in every workspace repo and summarize results")
+	c.Flags().StringVar(&testJUnit, "junit", "", "With --all, write JUnit XML results to this file")
+
+	runSingle := c.RunE
+	c.RunE = func(cmd *cobra.Command, args []string) error {
+		if !testAll {
+			if testJUnit != "" {
+				return fmt.Errorf("--junit requires --all")
+			}
+			return runSingle(cmd, args)
+		}
+		return runTestsAllRepos(args)
+	}
+}
+
+func runTestsAllRepos(extraArgs []string) error {
+	wsPath, err := workspace.Find()
+	if err != nil {
+		return err
+	}
+
+	ws, err := workspace.Load(wsPath)
+	if err != nil {
+		return err
+	}
+
+	wsEnv := buildWorkspaceEnv(wsPath, ws)
+	extraArgs = shellQuoteArgs(extraArgs)
+
+	names := make([]string, 0, len(ws.Repos))
+	for name := range ws.Repos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]testResult, 0, len(names))
+	for _, name := range names {
+		repo := ws.Repos[name]
+		repoDir := filepath.Join(wsPath, repo.Path)
+
+		if _, err := os.Stat(repoDir); os.IsNotExist(err) {
+			results = append(results, testResult{name: name, status: "skipped", message: "not cloned"})
+			continue
+		}
+
+		repoEnv := workspace.RepoEnv(wsEnv, repo)
+		projType := detectProjectType(repoDir)
+		command, err := resolveRepoCommand(repo, name, repoDir, projType, "test", extraArgs)
+		if err != nil {
+			results = append(results, testResult{name: name, command: command, status: "failed", message: err.Error()})
+			continue
+		}
+		if command == "" {
+			results = append(results, testResult{name: name, status: "skipped", message: "no test script"})
+			continue
+		}
+
+		if projType == projectTypeNode {
+			if err := ensureNodeModules(repoDir, repo.InstallCommand(), repoEnv); err != nil {
+				results = append(results, testResult{name: name, command: command, status: "failed", message: err.Error()})
+				continue
+			}
+		}
+
+		fmt.Printf("=== %s: %s ===\n", name, command)
+		start := time.Now()
+		err = runShellCmdWithEnv(repoDir, command, repoEnv)
+		result := testResult{name: name, command: command, status: "passed", duration: time.Since(start)}
+		if err != nil {
+			result.status = "failed"
+			result.message = err.Error()
+		}
+		results = append(results, result)
+		fmt.Println()
+	}
+
+	failed := printTestSummary(results)
+
+	if testJUnit != "" {
+		if err := writeJUnit(testJUnit, ws.Name, results); err != nil {
+			return err
+		}
+		fmt.Printf("JUnit results written to %s\n", testJUnit)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d repo(s) failed tests", failed)
+	}
+	return nil
+}
+
+// printTestSummary prints one line per repo and returns the number of failures
+func printTestSummary(results []testResult) int {
+	var passed, skipped, failed int
+	fmt.Println("Test summary:")
+	for _, r := range results {
+		icon := "✓"
+		switch r.status {
+		case "skipped":
+			icon = "⏭"
+			skipped++
+		case "failed":
+			icon = "✗"
+			failed++
+		default:
+			passed++
+		}
+		line := fmt.Sprintf("%s %-25s %-8s", icon, r.name, r.status)
+		if r.duration > 0 {
+			line += fmt.Sprintf(" %6.1fs", r.duration.Seconds())
+		}
+		if r.message != "" {
+			line += " — " + r.message
+		}
+		fmt.Println(line)
+	}
+	fmt.Printf("\n%d passed, %d skipped, %d failed\n", passed, skipped, failed)
+	return failed
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// writeJUnit serializes per-repo test results as a single JUnit test suite
+func writeJUnit(path, suiteName string, results []testResult) error {
+	suite := junitTestSuite{Name: suiteName, Tests: len(results)}
+	var total time.Duration
+	for _, r := range results {
+		tc := junitTestCase{
+			Name:      r.name,
+			ClassName: suiteName,
+			Time:      fmt.Sprintf("%.3f", r.duration.Seconds()),
+		}
+		switch r.status {
+		case "failed":
+			suite.Failures++
+			tc.Failure = &junitMessage{Message: r.message, Body: r.command}
+		case "skipped":
+			suite.Skipped++
+			tc.Skipped = &junitMessage{Message: r.message}
+		}
+		total += r.duration
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Time = fmt.Sprintf("%.3f", total.Seconds())
+
+	data, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JUnit results: %w", err)
+	}
+	data = append([]byte(xml.Header), data...)
+	data = append(data, '\n')
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
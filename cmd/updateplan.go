@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+)
+
+// sparkUpdateRow is one repo × package line of the --update --dry-run table
+type sparkUpdateRow struct {
+	repo      string
+	pkg       string
+	declared  string // range in package.json
+	installed string // version in node_modules, "" if not installed
+}
+
+// previewSparkUpdates prints, for every repo sync --update would touch, each
+// @spark-rewards package with its declared, installed and latest versions.
+// Nothing is installed or written.
+func previewSparkUpdates(wsPath string, ws *workspace.Workspace, args []string) error {
+	names := sortedRepoNames(ws)
+	if len(args) == 1 {
+		if _, ok := ws.Repos[args[0]]; !ok {
+			return fmt.Errorf("repo '%s' not found — run 'spark-cli list' to see repos", args[0])
+		}
+		names = args
+	} else if syncReposFile != "" {
+		listed, err := readReposFile(syncReposFile, ws)
+		if err != nil {
+			return err
+		}
+		names = listed
+	} else if syncGroup != "" {
+		group, err := workspace.GroupRepos(ws, syncGroup)
+		if err != nil {
+			return err
+		}
+		names = group
+	}
+
+	var rows []sparkUpdateRow
+	lookupDir := make(map[string]string) // package → a repo dir to run npm view in (uses its .npmrc)
+	for _, name := range names {
+		repo := ws.Repos[name]
+		repoDir := filepath.Join(wsPath, repo.Path)
+		if len(args) == 0 && workspace.SkipsSync(repo, repoDir) {
+			continue
+		}
+		declared := make(map[string]string)
+		for _, dir := range npmPackageDirs(repoDir) {
+			for k, v := range declaredDependencies(filepath.Join(repoDir, dir)) {
+				if _, ok := declared[k]; !ok {
+					declared[k] = v
+				}
+			}
+		}
+		for _, pkg := range filterPackages(findSparkPackages(repoDir), syncUpdateFilter) {
+			rows = append(rows, sparkUpdateRow{
+				repo:      name,
+				pkg:       pkg,
+				declared:  declared[pkg],
+				installed: installedVersion(repoDir, pkg),
+			})
+			if _, ok := lookupDir[pkg]; !ok {
+				lookupDir[pkg] = repoDir
+			}
+		}
+	}
+	if len(rows) == 0 {
+		fmt.Println("No @spark-rewards packages to update")
+		return nil
+	}
+
+	fmt.Printf("Resolving latest versions of %d package(s)...\n\n", len(lookupDir))
+	wsEnv := buildSyncEnv(wsPath, ws)
+	latest := make(map[string]string, len(lookupDir))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for pkg, dir := range lookupDir {
+		wg.Add(1)
+		go func(pkg, dir string) {
+			defer wg.Done()
+			version := "?"
+			if out, err := newSyncCmd(dir, "npm view "+pkg+" version", wsEnv).Output(); err == nil {
+				version = strings.TrimSpace(string(out))
+			}
+			mu.Lock()
+			latest[pkg] = version
+			mu.Unlock()
+		}(pkg, dir)
+	}
+	wg.Wait()
+
+	fmt.Printf("%-25s %-35s %-12s %-12s %s\n", "REPO", "PACKAGE", "DECLARED", "INSTALLED", "LATEST")
+	var outdated int
+	for _, r := range rows {
+		marker := ""
+		if l := latest[r.pkg]; l != "?" && r.installed != l {
+			marker = "  ← update"
+			outdated++
+		}
+		fmt.Printf("%-25s %-35s %-12s %-12s %s%s\n", r.repo, r.pkg, orDefault(r.declared, "-"), orDefault(r.installed, "-"), latest[r.pkg], marker)
+	}
+	fmt.Printf("\n%d of %d package install(s) would change (dry run — nothing installed)\n", outdated, len(rows))
+	return nil
+}
+
+// declaredDependencies returns one package.json's dependencies and devDependencies, name → range
+func declaredDependencies(repoDir string) map[string]string {
+	data, err := os.ReadFile(filepath.Join(repoDir, "package.json"))
+	if err != nil {
+		return nil
+	}
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil
+	}
+	deps := make(map[string]string, len(pkg.Dependencies)+len(pkg.DevDependencies))
+	for k, v := range pkg.DevDependencies {
+		deps[k] = v
+	}
+	for k, v := range pkg.Dependencies {
+		deps[k] = v
+	}
+	return deps
+}
+
+// installedVersion returns the version of pkg installed in the repo's node_modules, or ""
+func installedVersion(repoDir, pkg string) string {
+	data, err := os.ReadFile(filepath.Join(repoDir, "node_modules", pkg, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var meta struct {
+		Version string `json:"version"`
+	}
+	if json.Unmarshal(data, &meta) != nil {
+		return ""
+	}
+	return meta.Version
+}
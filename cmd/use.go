@@ -14,6 +14,7 @@ import (
 var (
 	useBuildCmd string
 	useDeps     []string
+	useInstall  bool
 )
 
 const defaultGitHubOrg = "Spark-Rewards"
@@ -26,10 +27,17 @@ in the workspace manifest.
 
 If only a repo name is provided, it defaults to the Spark-Rewards org.
 
+org/repo names become SSH URLs unless workspace.json sets
+"remote_protocol": "https" (for CI or machines without SSH keys). With
+"remote_token": true as well, git authenticates to github.com with the
+resolved GITHUB_TOKEN through a one-off credential helper; the token is never
+written to the remote URL or .git/config.
+
 Examples:
   spark-cli use BusinessAPI                              # clones Spark-Rewards/BusinessAPI
   spark-cli use other-org/SomeRepo                       # clones other-org/SomeRepo
-  spark-cli use git@github.com:other-org/Repo.git        # full URL`,
+  spark-cli use git@github.com:other-org/Repo.git        # full URL
+  spark-cli use AppAPI --install                         # clone, then npm install`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		repoArg := args[0]
@@ -40,37 +48,74 @@ Examples:
 			return fmt.Errorf("you must be inside a spark-cli workspace — run 'spark-cli create workspace <path>' first")
 		}
 
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+		if err := git.SetRemoteProtocol(ws.RemoteProtocol); err != nil {
+			return err
+		}
+
 		// Resolve the remote URL
 		remote := resolveRemote(repoArg)
 		repoName := git.RepoNameFromRemote(repoArg)
 		targetDir := filepath.Join(wsPath, repoName)
 
-		// Check if already cloned
-		if _, err := os.Stat(targetDir); err == nil {
-			if git.IsRepo(targetDir) {
-				fmt.Printf("Repository '%s' already exists at %s\n", repoName, targetDir)
-				// Still register it in manifest if not present
-				return registerRepo(wsPath, repoName, remote, targetDir)
-			}
+		if ws.RemoteToken {
+			git.SetCredentialToken(buildWorkspaceEnv(wsPath, ws)["GITHUB_TOKEN"])
+		}
+
+		if _, err := os.Stat(targetDir); err == nil && !git.IsRepo(targetDir) {
 			return fmt.Errorf("directory %s exists but is not a git repository", targetDir)
 		}
 
-		// Clone
-		fmt.Printf("Cloning %s into %s...\n", remote, targetDir)
-		if err := git.Clone(remote, targetDir); err != nil {
-			return fmt.Errorf("git clone failed: %w", err)
+		// Clone, or fast-forward the default branch if already cloned
+		if git.IsRepo(targetDir) {
+			fmt.Printf("Repository '%s' already exists at %s — updating...\n", repoName, targetDir)
+		} else {
+			fmt.Printf("Cloning %s into %s...\n", remote, targetDir)
+		}
+		cloned, err := git.CloneOrUpdate(remote, targetDir)
+		if err != nil {
+			if cloned {
+				return fmt.Errorf("git clone failed: %w", err)
+			}
+			fmt.Printf("Warning: could not update %s: %v\n", repoName, err)
 		}
 
 		// Register in workspace manifest
-		if err := registerRepo(wsPath, repoName, remote, targetDir); err != nil {
+		if err := registerRepo(cmd, wsPath, repoName, remote, targetDir); err != nil {
 			return err
 		}
 
 		fmt.Printf("Repository '%s' added to workspace\n", repoName)
+
+		if useInstall && cloned {
+			postCloneInstall(wsPath, repoName, targetDir)
+		}
 		return nil
 	},
 }
 
+// postCloneInstall installs a freshly cloned repo's dependencies with the
+// workspace env and, for CDK/Lambda repos, links the CDK dependencies.
+func postCloneInstall(wsPath, name, repoDir string) {
+	ws, err := workspace.Load(wsPath)
+	if err != nil {
+		fmt.Printf("Warning: skipping install: %v\n", err)
+		return
+	}
+
+	installRepo(wsPath, ws, name, repoDir)
+
+	for _, m := range cdkLambdaMappings {
+		if name == m.CDK || name == m.Lambda {
+			linkCDKDependencies(wsPath)
+			break
+		}
+	}
+}
+
 func resolveRemote(arg string) string {
 	// If it's already a full URL, use as-is
 	if git.BuildRemoteURL(arg) == arg {
@@ -99,13 +144,22 @@ func containsSlash(s string) bool {
 	return false
 }
 
-func registerRepo(wsPath, name, remote, targetDir string) error {
+// registerRepo adds the repo to the manifest, or updates its remote and path
+// if it's already there, keeping the rest of its existing config.
+func registerRepo(cmd *cobra.Command, wsPath, name, remote, targetDir string) error {
+	ws, err := workspace.Load(wsPath)
+	if err != nil {
+		return err
+	}
 	relPath, _ := filepath.Rel(wsPath, targetDir)
-	repo := workspace.RepoDef{
-		Remote:       remote,
-		Path:         relPath,
-		BuildCommand: useBuildCmd,
-		Dependencies: useDeps,
+	repo, exists := ws.Repos[name]
+	repo.Remote = remote
+	repo.Path = relPath
+	if !exists || cmd.Flags().Changed("build") {
+		repo.BuildCommand = useBuildCmd
+	}
+	if !exists || cmd.Flags().Changed("deps") {
+		repo.Dependencies = useDeps
 	}
 	if err := workspace.AddRepo(wsPath, name, repo); err != nil {
 		return err
@@ -120,5 +174,6 @@ func registerRepo(wsPath, name, remote, targetDir string) error {
 func init() {
 	useCmd.Flags().StringVar(&useBuildCmd, "build", "", "Build command for this repo (e.g., 'npm run build')")
 	useCmd.Flags().StringSliceVar(&useDeps, "deps", nil, "Dependencies (other repo names that must build first)")
+	useCmd.Flags().BoolVar(&useInstall, "install", false, "Install dependencies (npm install) right after cloning")
 	rootCmd.AddCommand(useCmd)
 }
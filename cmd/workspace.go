@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/aws"
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
@@ -16,8 +18,28 @@ var (
 	workspaceCreateRegion  string
 	workspaceConfigureProfile string
 	workspaceConfigureList    bool
+	workspaceJSON             bool
 )
 
+// workspaceRepoStatus is one repo's entry in 'workspace --json' output
+type workspaceRepoStatus struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Branch      string `json:"branch"`
+	Status      string `json:"status"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// workspaceStatus is the machine-readable form of 'workspace' output
+type workspaceStatus struct {
+	Workspace   string                `json:"workspace"`
+	Path        string                `json:"path"`
+	AWSProfile  string                `json:"aws_profile"`
+	Environment string                `json:"environment"`
+	Healthy     bool                  `json:"healthy"`
+	Repos       []workspaceRepoStatus `json:"repos"`
+}
+
 var workspaceCmd = &cobra.Command{
 	Use:     "workspace",
 	Short:   "Manage workspace (ws, info | create | configure --profile, --list | -h)",
@@ -29,6 +51,8 @@ With no subcommand, lists the workspace name, repos, and AWS profile.
 
 Examples:
   spark-cli workspace                    # or: spark-cli ws
+  spark-cli ws --json                    # machine-readable status for scripts/CI
+  spark-cli ws info                      # configuration overview
   spark-cli ws create [path]             # create a new workspace
   spark-cli workspace configure --profile dev   # set default AWS profile`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -42,6 +66,10 @@ Examples:
 			return err
 		}
 
+		if workspaceJSON {
+			return printWorkspaceStatusJSON(wsPath, ws)
+		}
+
 		fmt.Printf("%-15s %-30s %-25s %s\n", "WORKSPACE", "LOCATION", "AWS PROFILE", "ENVIRONMENT")
 		fmt.Printf("%-15s %-30s %-25s %s\n", "---------", "--------", "------------", "-----------")
 		fmt.Printf("%-15s %-30s %-25s %s\n", ws.Name, wsPath, orDefault(ws.AWSProfile, "(not set)"), orDefault(ws.SSMEnvPath, "beta"))
@@ -66,24 +94,7 @@ Examples:
 			fmt.Printf("%-20s %-15s %-10s %s\n", "----", "------", "------", "----")
 
 			for name, repo := range ws.Repos {
-				repoDir := filepath.Join(wsPath, repo.Path)
-				branch := "-"
-				status := "missing"
-
-				if _, err := os.Stat(repoDir); err == nil {
-					if git.IsRepo(repoDir) {
-						b, _ := git.CurrentBranch(repoDir)
-						if b != "" {
-							branch = b
-						}
-						if git.IsDirty(repoDir) {
-							status = "unstaged-changes"
-						} else {
-							status = "up-to-date"
-						}
-					}
-				}
-
+				branch, status := repoBranchStatus(filepath.Join(wsPath, repo.Path))
 				fmt.Printf("%-20s %-15s %-10s %s\n", name, branch, status, repo.Path)
 			}
 		} else {
@@ -257,6 +268,63 @@ func runWorkspaceConfigureProfile(profileName string) error {
 	return nil
 }
 
+// repoBranchStatus returns a repo's current branch ("-" if unknown) and one of
+// "missing", "unstaged-changes" or "up-to-date".
+func repoBranchStatus(repoDir string) (string, string) {
+	branch := "-"
+	status := "missing"
+
+	if _, err := os.Stat(repoDir); err == nil {
+		if git.IsRepo(repoDir) {
+			b, _ := git.CurrentBranch(repoDir)
+			if b != "" {
+				branch = b
+			}
+			if git.IsDirty(repoDir) {
+				status = "unstaged-changes"
+			} else {
+				status = "up-to-date"
+			}
+		}
+	}
+	return branch, status
+}
+
+func printWorkspaceStatusJSON(wsPath string, ws *workspace.Workspace) error {
+	out := workspaceStatus{
+		Workspace:   ws.Name,
+		Path:        wsPath,
+		AWSProfile:  ws.AWSProfile,
+		Environment: orDefault(ws.SSMEnvPath, "beta"),
+		Healthy:     true,
+		Repos:       []workspaceRepoStatus{},
+	}
+
+	names := make([]string, 0, len(ws.Repos))
+	for name := range ws.Repos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		repo := ws.Repos[name]
+		branch, status := repoBranchStatus(filepath.Join(wsPath, repo.Path))
+		entry := workspaceRepoStatus{Name: name, Path: repo.Path, Branch: branch, Status: status}
+		if status == "missing" {
+			entry.Remediation = fmt.Sprintf("spark-cli use %s", repo.Remote)
+			out.Healthy = false
+		}
+		out.Repos = append(out.Repos, entry)
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal status: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
 func orDefault(val, def string) string {
 	if val == "" {
 		return def
@@ -270,6 +338,8 @@ func init() {
 	workspaceCmd.AddCommand(workspaceConfigureCmd)
 	workspaceConfigureCmd.AddCommand(workspaceConfigureSSOCmd)
 
+	workspaceCmd.Flags().BoolVar(&workspaceJSON, "json", false, "Print workspace and repo status as JSON")
+
 	workspaceCreateCmd.Flags().StringVar(&workspaceCreateProfile, "aws-profile", "", "AWS SSO profile name")
 	workspaceCreateCmd.Flags().StringVar(&workspaceCreateRegion, "aws-region", "", "Default AWS region")
 
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/aws"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/git"
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// wsSetting is one editable workspace.json field
+type wsSetting struct {
+	key      string
+	desc     string
+	get      func(ws *workspace.Workspace) string
+	set      func(ws *workspace.Workspace, v string)
+	validate func(wsPath string, ws *workspace.Workspace, v string) error
+}
+
+var awsRegionPattern = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]*)?-[a-z]+-\d+$`)
+var ssmEnvPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+var wsSettings = []wsSetting{
+	{
+		key:  "aws_profile",
+		desc: "AWS profile used by sync/env refresh",
+		get:  func(ws *workspace.Workspace) string { return ws.AWSProfile },
+		set:  func(ws *workspace.Workspace, v string) { ws.AWSProfile = v },
+		validate: func(wsPath string, ws *workspace.Workspace, v string) error {
+			if v == "" {
+				return nil
+			}
+			for _, p := range aws.GetSSOProfiles() {
+				if p == v {
+					return nil
+				}
+			}
+			fmt.Printf("Note: profile %q not found in ~/.aws/config (setting it anyway).\n", v)
+			return nil
+		},
+	},
+	{
+		key:  "aws_region",
+		desc: "AWS region for SSM lookups (default us-east-1)",
+		get:  func(ws *workspace.Workspace) string { return ws.AWSRegion },
+		set:  func(ws *workspace.Workspace, v string) { ws.AWSRegion = v },
+		validate: func(wsPath string, ws *workspace.Workspace, v string) error {
+			if v != "" && !awsRegionPattern.MatchString(v) {
+				return fmt.Errorf("invalid AWS region %q (expected e.g. us-east-1)", v)
+			}
+			return nil
+		},
+	},
+	{
+		key:      "default_branch",
+		desc:     "Branch repos are rebased onto during sync (unset: each repo's remote HEAD)",
+		get:      func(ws *workspace.Workspace) string { return ws.DefaultBranch },
+		set:      func(ws *workspace.Workspace, v string) { ws.DefaultBranch = v },
+		validate: validateDefaultBranch,
+	},
+	{
+		key:  "ssm_env_path",
+		desc: "SSM environment under /app/<env>/ (default beta)",
+		get:  func(ws *workspace.Workspace) string { return ws.SSMEnvPath },
+		set:  func(ws *workspace.Workspace, v string) { ws.SSMEnvPath = v },
+		validate: func(wsPath string, ws *workspace.Workspace, v string) error {
+			if v != "" && !ssmEnvPattern.MatchString(v) {
+				return fmt.Errorf("invalid SSM environment %q", v)
+			}
+			return nil
+		},
+	},
+	{
+		key:  "auto_refresh_env",
+		desc: "Refresh .env from ssm_env_path on every sync, without --env (on/off)",
+		get: func(ws *workspace.Workspace) string {
+			if ws.AutoRefreshEnv {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(ws *workspace.Workspace, v string) { ws.AutoRefreshEnv = v == "on" },
+		validate: func(wsPath string, ws *workspace.Workspace, v string) error {
+			if v != "on" && v != "off" {
+				return fmt.Errorf("auto_refresh_env must be 'on' or 'off'")
+			}
+			return nil
+		},
+	},
+	{
+		key:  "vscode",
+		desc: "Generate the .code-workspace file (on/off)",
+		get: func(ws *workspace.Workspace) string {
+			if ws.VSCode != nil && ws.VSCode.Disabled {
+				return "off"
+			}
+			return "on"
+		},
+		set: func(ws *workspace.Workspace, v string) {
+			if ws.VSCode == nil {
+				ws.VSCode = &workspace.VSCodeConfig{}
+			}
+			ws.VSCode.Disabled = v == "off"
+		},
+		validate: func(wsPath string, ws *workspace.Workspace, v string) error {
+			if v != "on" && v != "off" {
+				return fmt.Errorf("vscode must be 'on' or 'off'")
+			}
+			return nil
+		},
+	},
+}
+
+// validateDefaultBranch requires <branch> to exist on the sync remote of at
+// least one cloned repo
+func validateDefaultBranch(wsPath string, ws *workspace.Workspace, v string) error {
+	if v == "" {
+		return nil
+	}
+	cloned := 0
+	for _, repo := range ws.Repos {
+		repoDir := filepath.Join(wsPath, repo.Path)
+		if !git.IsRepo(repoDir) {
+			continue
+		}
+		cloned++
+		if git.RemoteBranchExists(repoDir, repo.UpstreamRemote(), v) {
+			return nil
+		}
+	}
+	if cloned == 0 {
+		return nil
+	}
+	return fmt.Errorf("branch %q not found on the sync remote of any cloned repo", v)
+}
+
+func findSetting(key string) (*wsSetting, error) {
+	for i := range wsSettings {
+		if wsSettings[i].key == key {
+			return &wsSettings[i], nil
+		}
+	}
+	var keys []string
+	for _, s := range wsSettings {
+		keys = append(keys, s.key)
+	}
+	return nil, fmt.Errorf("unknown key %q — valid keys: %s", key, strings.Join(keys, ", "))
+}
+
+var workspaceConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View or change workspace settings (get | set)",
+	Long: `Shows workspace settings from .spk/workspace.json, or reads/writes one
+setting with validation.
+
+Keys: aws_profile, aws_region, default_branch, ssm_env_path, vscode
+
+Examples:
+  spark-cli workspace config                          # list all settings
+  spark-cli workspace config get aws_region
+  spark-cli workspace config set aws_region us-west-2
+  spark-cli workspace config set default_branch ""    # clear a setting
+  spark-cli workspace config set vscode off           # stop generating .code-workspace`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+		for _, s := range wsSettings {
+			fmt.Printf("%-16s %-20s # %s\n", s.key, orDefault(s.get(ws), "(not set)"), s.desc)
+		}
+		return nil
+	},
+}
+
+var workspaceConfigGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a workspace setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setting, err := findSetting(args[0])
+		if err != nil {
+			return err
+		}
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+		fmt.Println(setting.get(ws))
+		return nil
+	},
+}
+
+var workspaceConfigSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Validate and save a workspace setting",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setting, err := findSetting(args[0])
+		if err != nil {
+			return err
+		}
+		wsPath, err := workspace.Find()
+		if err != nil {
+			return err
+		}
+		ws, err := workspace.Load(wsPath)
+		if err != nil {
+			return err
+		}
+		value := strings.TrimSpace(args[1])
+		if err := setting.validate(wsPath, ws, value); err != nil {
+			return err
+		}
+		setting.set(ws, value)
+		if err := workspace.Save(wsPath, ws); err != nil {
+			return fmt.Errorf("failed to save workspace: %w", err)
+		}
+		fmt.Printf("%s = %s\n", setting.key, orDefault(value, "(not set)"))
+		return nil
+	},
+}
+
+func init() {
+	workspaceConfigCmd.AddCommand(workspaceConfigGetCmd)
+	workspaceConfigCmd.AddCommand(workspaceConfigSetCmd)
+	workspaceCmd.AddCommand(workspaceConfigCmd)
+}
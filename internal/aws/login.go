@@ -47,30 +47,6 @@ func CheckCLI() error {
 	return nil
 }
 
-// GetCallerIdentity runs `aws sts get-caller-identity` to verify credentials
-func GetCallerIdentity(profile string) error {
-	args := []string{"sts", "get-caller-identity"}
-	if profile != "" {
-		args = append(args, "--profile", profile)
-	}
-
-	cmd := exec.Command("aws", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
-}
-
-// GetCallerIdentityQuiet verifies credentials without printing output
-func GetCallerIdentityQuiet(profile string) error {
-	args := []string{"sts", "get-caller-identity"}
-	if profile != "" {
-		args = append(args, "--profile", profile)
-	}
-
-	cmd := exec.Command("aws", args...)
-	return cmd.Run()
-}
-
 // GetSSOProfiles returns a list of SSO-configured profiles from ~/.aws/config
 func GetSSOProfiles() []string {
 	configPath := filepath.Join(os.Getenv("HOME"), ".aws", "config")
@@ -106,6 +82,40 @@ func GetSSOProfiles() []string {
 	return profiles
 }
 
+// ProfileExists reports whether profile is defined in the AWS config or
+// credentials file (honoring AWS_CONFIG_FILE / AWS_SHARED_CREDENTIALS_FILE).
+// The default profile always counts, since it may come from env vars or a role.
+func ProfileExists(profile string) bool {
+	if profile == "" || profile == "default" {
+		return true
+	}
+	configPath := os.Getenv("AWS_CONFIG_FILE")
+	if configPath == "" {
+		configPath = filepath.Join(os.Getenv("HOME"), ".aws", "config")
+	}
+	credsPath := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if credsPath == "" {
+		credsPath = filepath.Join(os.Getenv("HOME"), ".aws", "credentials")
+	}
+	return hasSection(configPath, "profile "+profile) || hasSection(credsPath, profile)
+}
+
+// hasSection reports whether an INI file has a [name] section header
+func hasSection(path, name string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") &&
+			strings.Join(strings.Fields(line[1:len(line)-1]), " ") == name {
+			return true
+		}
+	}
+	return false
+}
+
 // IsSSOConfigured checks if a profile has SSO configuration
 func IsSSOConfigured(profile string) bool {
 	if profile == "" {
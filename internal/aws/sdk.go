@@ -0,0 +1,94 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// LoadSDKConfig loads AWS SDK config for a named profile from ~/.aws/config,
+// including cached SSO credentials from a prior `aws sso login`.
+func LoadSDKConfig(ctx context.Context, profile, region string) (awssdk.Config, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	if profile != "" {
+		opts = append(opts, awsconfig.WithSharedConfigProfile(profile))
+	}
+	if region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return awssdk.Config{}, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return cfg, nil
+}
+
+// callerIdentity mirrors the output shape of `aws sts get-caller-identity`
+type callerIdentity struct {
+	UserId  string `json:"UserId"`
+	Account string `json:"Account"`
+	Arn     string `json:"Arn"`
+}
+
+// stsTimeout bounds the credential check so an unreachable endpoint can't hang sync
+const stsTimeout = 15 * time.Second
+
+func callerIdentityFor(profile string) (*callerIdentity, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), stsTimeout)
+	defer cancel()
+	cfg, err := LoadSDKConfig(ctx, profile, "")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+
+	out, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("sts get-caller-identity timed out after %s — check your credentials/network: %w", stsTimeout, ctx.Err())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &callerIdentity{
+		UserId:  awssdk.ToString(out.UserId),
+		Account: awssdk.ToString(out.Account),
+		Arn:     awssdk.ToString(out.Arn),
+	}, nil
+}
+
+// GetCallerIdentity verifies credentials via STS and prints the identity
+func GetCallerIdentity(profile string) error {
+	id, err := callerIdentityFor(profile)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(id, "", "    ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// AccountID returns the AWS account ID the profile's credentials belong to
+func AccountID(profile string) (string, error) {
+	id, err := callerIdentityFor(profile)
+	if err != nil {
+		return "", err
+	}
+	return id.Account, nil
+}
+
+// GetCallerIdentityQuiet verifies credentials without printing output
+func GetCallerIdentityQuiet(profile string) error {
+	_, err := callerIdentityFor(profile)
+	return err
+}
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -9,17 +10,61 @@ import (
 	"strings"
 )
 
-// Clone clones a repository into the target directory
+// cmdCtx bounds every git subprocess started by this package
+var cmdCtx = context.Background()
+
+// SetContext makes subsequent git commands run under ctx; they are killed when it is cancelled
+func SetContext(ctx context.Context) {
+	cmdCtx = ctx
+}
+
+// command builds a git subprocess bound to the package context
+func command(name string, args ...string) *exec.Cmd {
+	return exec.CommandContext(cmdCtx, name, args...)
+}
+
+// Clone clones a repository into the target directory, authenticating with
+// the credential token (see SetCredentialToken) when one is set
 func Clone(remote, targetDir string) error {
-	cmd := exec.Command("git", "clone", remote, targetDir)
+	cmd := credentialCommand("clone", remote, targetDir)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
-// Pull runs git pull in the given directory
-func Pull(repoDir string) error {
-	cmd := exec.Command("git", "pull")
+// CloneOrUpdate clones remote into targetDir, or, if targetDir is already a
+// repo, fetches origin and fast-forwards the default branch. Reports whether
+// a fresh clone happened.
+func CloneOrUpdate(remote, targetDir string) (bool, error) {
+	if !IsRepo(targetDir) {
+		return true, Clone(remote, targetDir)
+	}
+	if err := FetchQuiet(targetDir, "origin"); err != nil {
+		return false, fmt.Errorf("fetch failed: %w", err)
+	}
+	branch := GetDefaultBranch(targetDir)
+	if GetCurrentBranch(targetDir) == branch {
+		err := runQuiet(targetDir, "git", "merge", "--ff-only", "origin/"+branch)
+		if err != nil {
+			return false, fmt.Errorf("cannot fast-forward %s: %w", branch, err)
+		}
+		return false, nil
+	}
+	// Not checked out: update the local branch ref directly (fast-forward only)
+	if err := fetchQuiet(targetDir, "origin", branch+":"+branch); err != nil {
+		return false, fmt.Errorf("cannot fast-forward %s: %w", branch, err)
+	}
+	return false, nil
+}
+
+// PullFFOnly runs git pull --ff-only, ignoring the user's pull.rebase/pull.ff
+// config: the branch is fast-forwarded or the pull fails.
+func PullFFOnly(repoDir string) error {
+	return pull(repoDir, "--no-rebase", "--ff-only")
+}
+
+func pull(repoDir string, args ...string) error {
+	cmd := command("git", append([]string{"pull"}, args...)...)
 	cmd.Dir = repoDir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -28,7 +73,7 @@ func Pull(repoDir string) error {
 
 // Status runs git status in the given directory and returns the output
 func Status(repoDir string) (string, error) {
-	cmd := exec.Command("git", "status", "--short")
+	cmd := command("git", "status", "--short")
 	cmd.Dir = repoDir
 	out, err := cmd.Output()
 	if err != nil {
@@ -39,7 +84,7 @@ func Status(repoDir string) (string, error) {
 
 // StatusLong returns full git status output (for showing unstaged changes when unable to rebase)
 func StatusLong(repoDir string) (string, error) {
-	cmd := exec.Command("git", "status")
+	cmd := command("git", "status")
 	cmd.Dir = repoDir
 	out, err := cmd.Output()
 	if err != nil {
@@ -48,9 +93,29 @@ func StatusLong(repoDir string) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
-// StatusShortColor returns git status --short with ANSI colors (staged vs unstaged like git status)
-func StatusShortColor(repoDir string) (string, error) {
-	cmd := exec.Command("git", "status", "--short", "--color=always")
+// StatusShortColor returns git status --short, with ANSI colors (staged vs unstaged like git status) when color is true
+func StatusShortColor(repoDir string, color bool) (string, error) {
+	colorFlag := "--color=never"
+	if color {
+		colorFlag = "--color=always"
+	}
+	cmd := command("git", "status", "--short", colorFlag)
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// DiffStat returns `git diff --stat base...HEAD`: what HEAD changes since it
+// forked from base, ignoring commits base gained since. ANSI colors when color is true.
+func DiffStat(repoDir, base string, color bool) (string, error) {
+	colorArg := "--color=never"
+	if color {
+		colorArg = "--color=always"
+	}
+	cmd := command("git", "diff", "--stat", colorArg, base+"...HEAD")
 	cmd.Dir = repoDir
 	out, err := cmd.Output()
 	if err != nil {
@@ -61,7 +126,7 @@ func StatusShortColor(repoDir string) (string, error) {
 
 // CurrentBranch returns the current branch name
 func CurrentBranch(repoDir string) (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd := command("git", "rev-parse", "--abbrev-ref", "HEAD")
 	cmd.Dir = repoDir
 	out, err := cmd.Output()
 	if err != nil {
@@ -70,6 +135,36 @@ func CurrentBranch(repoDir string) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// HeadSHA returns the full commit SHA of HEAD
+func HeadSHA(repoDir string) (string, error) {
+	cmd := command("git", "rev-parse", "HEAD")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// BranchSHA returns the commit a local branch points at
+func BranchSHA(repoDir, branch string) (string, error) {
+	cmd := command("git", "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("branch %s not found: %w", branch, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// BackupRefPrefix namespaces the refs sync writes before rewriting a branch
+const BackupRefPrefix = "refs/spk-backup/"
+
+// CreateBackupRef points refs/spk-backup/<branch> at sha, overwriting any older backup
+func CreateBackupRef(repoDir, branch, sha string) error {
+	return runQuiet(repoDir, "git", "update-ref", BackupRefPrefix+branch, sha)
+}
+
 // IsRepo checks if the given directory is a git repository
 func IsRepo(dir string) bool {
 	gitDir := filepath.Join(dir, ".git")
@@ -80,14 +175,77 @@ func IsRepo(dir string) bool {
 	return info.IsDir()
 }
 
-// BuildRemoteURL constructs a git SSH URL from org/repo
+// TopLevel returns the root of the git checkout containing dir, which may be an
+// ancestor of dir
+func TopLevel(dir string) (string, error) {
+	cmd := command("git", "rev-parse", "--show-toplevel")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s is not inside a git checkout", dir)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// remoteProtocol is "ssh" (default) or "https", set from the workspace's remote_protocol
+var remoteProtocol = "ssh"
+
+// SetRemoteProtocol selects the URL form BuildRemoteURL produces: "ssh" or "https"
+func SetRemoteProtocol(protocol string) error {
+	switch protocol {
+	case "":
+		remoteProtocol = "ssh"
+	case "ssh", "https":
+		remoteProtocol = protocol
+	default:
+		return fmt.Errorf("invalid remote_protocol %q (use ssh or https)", protocol)
+	}
+	return nil
+}
+
+// BuildRemoteURL constructs a GitHub URL from org/repo — SSH by default, or
+// https://github.com/org/repo.git when the remote protocol is https
 func BuildRemoteURL(orgRepo string) string {
 	if strings.HasPrefix(orgRepo, "git@") || strings.HasPrefix(orgRepo, "https://") {
 		return orgRepo
 	}
+	if remoteProtocol == "https" {
+		return fmt.Sprintf("https://github.com/%s.git", orgRepo)
+	}
 	return fmt.Sprintf("git@github.com:%s.git", orgRepo)
 }
 
+// RemoteURL returns the URL of the named remote as configured (before any
+// url.<base>.insteadOf rewriting)
+func RemoteURL(repoDir, remote string) (string, error) {
+	cmd := command("git", "config", "--get", "remote."+remote+".url")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// SameRemote reports whether two remote URLs point at the same repo,
+// treating SSH and HTTPS forms (and a trailing .git) as equivalent.
+func SameRemote(a, b string) bool {
+	return normalizeRemote(a) == normalizeRemote(b)
+}
+
+func normalizeRemote(url string) string {
+	url = strings.TrimSpace(strings.ToLower(url))
+	url = strings.TrimPrefix(url, "https://")
+	url = strings.TrimPrefix(url, "ssh://")
+	url = strings.TrimPrefix(url, "git@")
+	if at := strings.Index(url, "@"); at != -1 && !strings.Contains(url[:at], "/") {
+		url = url[at+1:] // drop https userinfo such as an embedded token
+	}
+	url = strings.Replace(url, ":", "/", 1)
+	url = strings.TrimSuffix(url, "/")
+	return strings.TrimSuffix(url, ".git")
+}
+
 // RepoNameFromRemote extracts the repo name from a remote URL or org/repo string
 func RepoNameFromRemote(remote string) string {
 	// Handle org/repo format
@@ -106,7 +264,7 @@ func Fetch(repoDir, remote string) error {
 	if remote == "" {
 		remote = "origin"
 	}
-	cmd := exec.Command("git", "fetch", remote)
+	cmd := command("git", "fetch", remote)
 	cmd.Dir = repoDir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -115,37 +273,143 @@ func Fetch(repoDir, remote string) error {
 
 // Rebase runs git rebase on the specified upstream branch
 func Rebase(repoDir, upstream string) error {
-	cmd := exec.Command("git", "rebase", upstream)
+	cmd := command("git", "rebase", upstream)
 	cmd.Dir = repoDir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
+// IsRebaseInProgress reports whether the repo is mid-rebase (a
+// rebase-merge or rebase-apply directory exists in its git dir)
+func IsRebaseInProgress(repoDir string) bool {
+	for _, name := range []string{"rebase-merge", "rebase-apply"} {
+		cmd := command("git", "rev-parse", "--git-path", name)
+		cmd.Dir = repoDir
+		out, err := cmd.Output()
+		if err != nil {
+			continue
+		}
+		path := strings.TrimSpace(string(out))
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(repoDir, path)
+		}
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
 // RebaseAbort aborts an in-progress rebase
 func RebaseAbort(repoDir string) error {
-	cmd := exec.Command("git", "rebase", "--abort")
+	cmd := command("git", "rebase", "--abort")
 	cmd.Dir = repoDir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
+// ConflictFiles returns paths with unresolved merge conflicts (e.g. mid-rebase)
+func ConflictFiles(repoDir string) []string {
+	cmd := command("git", "diff", "--name-only", "--diff-filter=U")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	raw := strings.TrimSpace(string(out))
+	if raw == "" {
+		return nil
+	}
+	return strings.Split(raw, "\n")
+}
+
+// ChangedFiles returns paths with uncommitted changes (staged, unstaged or untracked)
+func ChangedFiles(repoDir string) []string {
+	cmd := command("git", "status", "--porcelain")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if len(line) > 3 {
+			files = append(files, line[3:])
+		}
+	}
+	return files
+}
+
+// IsIgnored reports whether path (relative to repoDir) is excluded by the repo's gitignore rules
+func IsIgnored(repoDir, path string) bool {
+	return runQuiet(repoDir, "git", "check-ignore", "-q", path) == nil
+}
+
+// RestoreFile discards uncommitted changes to a single tracked file
+func RestoreFile(repoDir, path string) error {
+	return runQuiet(repoDir, "git", "checkout", "--", path)
+}
+
 // runQuiet runs a command with stdout/stderr discarded (for sync to avoid flooding output)
 func runQuiet(repoDir string, name string, args ...string) error {
-	cmd := exec.Command(name, args...)
+	cmd := command(name, args...)
 	cmd.Dir = repoDir
 	cmd.Stdout = io.Discard
 	cmd.Stderr = io.Discard
 	return cmd.Run()
 }
 
-// FetchQuiet runs git fetch with output suppressed
+// credentialToken, when set, answers github.com HTTPS credential requests from clones and quiet fetches
+var credentialToken string
+
+// tokenHelper is an inline credential helper reading the token from the
+// environment, so it never appears in process arguments or .git/config
+const tokenHelper = `credential.https://github.com.helper=!f() { test "$1" = get && echo username=x-access-token && echo "password=$SPK_GIT_TOKEN"; }; f`
+
+// SetCredentialToken makes clones and quiet fetches authenticate to github.com
+// over HTTPS with token (e.g. GITHUB_TOKEN) when no other credential helper answers
+func SetCredentialToken(token string) {
+	credentialToken = token
+}
+
+// credentialCommand builds a git command that offers the credential token
+// through tokenHelper for this invocation only
+func credentialCommand(args ...string) *exec.Cmd {
+	if credentialToken == "" {
+		cmd := command("git", args...)
+		cmd.Env = os.Environ()
+		return cmd
+	}
+	cmd := command("git", append([]string{"-c", tokenHelper}, args...)...)
+	cmd.Env = append(os.Environ(), "SPK_GIT_TOKEN="+credentialToken)
+	return cmd
+}
+
+// fetchQuiet runs git fetch with output suppressed and terminal prompts
+// disabled: fetches run in parallel, so a credential prompt would race the
+// others for stdin. A missing credential fails the fetch instead.
+func fetchQuiet(repoDir string, args ...string) error {
+	cmd := credentialCommand(append([]string{"fetch"}, args...)...)
+	cmd.Env = append(cmd.Env, "GIT_TERMINAL_PROMPT=0", "GCM_INTERACTIVE=never")
+	cmd.Dir = repoDir
+	cmd.Stdout = io.Discard
+	cmd.Stderr = io.Discard
+	return cmd.Run()
+}
+
+// FetchQuiet runs git fetch with output suppressed, never prompting for credentials
 func FetchQuiet(repoDir, remote string) error {
 	if remote == "" {
 		remote = "origin"
 	}
-	return runQuiet(repoDir, "git", "fetch", remote)
+	return fetchQuiet(repoDir, remote)
+}
+
+// FetchAllRemotes runs git fetch --all with output suppressed, never prompting for credentials
+func FetchAllRemotes(repoDir string) error {
+	return fetchQuiet(repoDir, "--all")
 }
 
 // RebaseQuiet runs git rebase with output suppressed
@@ -158,9 +422,19 @@ func RebaseAbortQuiet(repoDir string) error {
 	return runQuiet(repoDir, "git", "rebase", "--abort")
 }
 
+// MergeQuiet merges upstream into the current branch (no editor) with output suppressed
+func MergeQuiet(repoDir, upstream string) error {
+	return runQuiet(repoDir, "git", "merge", "--no-edit", upstream)
+}
+
+// MergeAbortQuiet aborts an in-progress merge with output suppressed
+func MergeAbortQuiet(repoDir string) error {
+	return runQuiet(repoDir, "git", "merge", "--abort")
+}
+
 // Stash stashes uncommitted changes
 func Stash(repoDir string) error {
-	cmd := exec.Command("git", "stash", "push", "-m", "spark-cli-sync-autostash")
+	cmd := command("git", "stash", "push", "-m", "spark-cli-sync-autostash")
 	cmd.Dir = repoDir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -169,16 +443,26 @@ func Stash(repoDir string) error {
 
 // StashPop pops the most recent stash
 func StashPop(repoDir string) error {
-	cmd := exec.Command("git", "stash", "pop")
+	cmd := command("git", "stash", "pop")
 	cmd.Dir = repoDir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
+// StashQuiet stashes all local changes, untracked files included, with output suppressed
+func StashQuiet(repoDir string) error {
+	return runQuiet(repoDir, "git", "stash", "push", "--include-untracked", "-m", "spark-cli-sync-autostash")
+}
+
+// StashPopQuiet pops the most recent stash with output suppressed
+func StashPopQuiet(repoDir string) error {
+	return runQuiet(repoDir, "git", "stash", "pop")
+}
+
 // HasStash checks if there are any stashed changes
 func HasStash(repoDir string) bool {
-	cmd := exec.Command("git", "stash", "list")
+	cmd := command("git", "stash", "list")
 	cmd.Dir = repoDir
 	out, err := cmd.Output()
 	if err != nil {
@@ -198,13 +482,13 @@ func IsDirty(repoDir string) bool {
 
 // IsUpToDate returns true if HEAD equals origin/targetBranch (e.g. after fetch)
 func IsUpToDate(repoDir, targetBranch string) bool {
-	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd := command("git", "rev-parse", "HEAD")
 	cmd.Dir = repoDir
 	head, err := cmd.Output()
 	if err != nil {
 		return false
 	}
-	cmd = exec.Command("git", "rev-parse", "origin/"+targetBranch)
+	cmd = command("git", "rev-parse", "origin/"+targetBranch)
 	cmd.Dir = repoDir
 	upstream, err := cmd.Output()
 	if err != nil {
@@ -224,7 +508,7 @@ func GetCurrentBranch(repoDir string) string {
 
 // ListLocalBranches returns all local branch names
 func ListLocalBranches(repoDir string) []string {
-	cmd := exec.Command("git", "for-each-ref", "--format=%(refname:short)", "refs/heads/")
+	cmd := command("git", "for-each-ref", "--format=%(refname:short)", "refs/heads/")
 	cmd.Dir = repoDir
 	out, err := cmd.Output()
 	if err != nil {
@@ -237,9 +521,20 @@ func ListLocalBranches(repoDir string) []string {
 	return strings.Split(raw, "\n")
 }
 
+// Remotes lists the repo's configured remote names
+func Remotes(repoDir string) []string {
+	cmd := command("git", "remote")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(out))
+}
+
 // AheadBehind returns how many commits local is ahead/behind upstream
 func AheadBehind(repoDir, local, upstream string) (ahead, behind int) {
-	cmd := exec.Command("git", "rev-list", "--left-right", "--count", fmt.Sprintf("%s...%s", local, upstream))
+	cmd := command("git", "rev-list", "--left-right", "--count", fmt.Sprintf("%s...%s", local, upstream))
 	cmd.Dir = repoDir
 	out, err := cmd.Output()
 	if err != nil {
@@ -253,26 +548,123 @@ func AheadBehind(repoDir, local, upstream string) (ahead, behind int) {
 	return
 }
 
+// Upstream returns the upstream tracking ref of a branch (e.g. "origin/feature"), or "" if none
+func Upstream(repoDir, branch string) string {
+	cmd := command("git", "rev-parse", "--abbrev-ref", "--symbolic-full-name", branch+"@{upstream}")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// HasUpstream reports whether branch has tracking configured, even if the
+// remote branch hasn't been fetched or pushed yet
+func HasUpstream(repoDir, branch string) bool {
+	return runQuiet(repoDir, "git", "config", "--get", "branch."+branch+".merge") == nil
+}
+
+// SetUpstream makes branch track remoteBranch (e.g. "origin/feature") by
+// writing branch.<name>.remote/merge, which works before the remote branch exists
+func SetUpstream(repoDir, branch, remoteBranch string) error {
+	remote, name, ok := strings.Cut(remoteBranch, "/")
+	if !ok || remote == "" || name == "" {
+		return fmt.Errorf("invalid upstream %q (want <remote>/<branch>)", remoteBranch)
+	}
+	if err := runQuiet(repoDir, "git", "config", "branch."+branch+".remote", remote); err != nil {
+		return err
+	}
+	return runQuiet(repoDir, "git", "config", "branch."+branch+".merge", "refs/heads/"+name)
+}
+
+// PushForceWithLease pushes a rewritten (e.g. rebased) branch to the remote
+// branch it tracks (branch.<name>.remote/merge), which may have a different
+// name, refusing to clobber remote commits that weren't fetched first. Never
+// uses plain --force.
+func PushForceWithLease(repoDir, branch string) error {
+	cmd := command("git", "config", "--get", "branch."+branch+".remote")
+	cmd.Dir = repoDir
+	remote, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("%s has no upstream remote", branch)
+	}
+	cmd = command("git", "config", "--get", "branch."+branch+".merge")
+	cmd.Dir = repoDir
+	merge, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("%s has no upstream branch", branch)
+	}
+	refspec := "refs/heads/" + branch + ":" + strings.TrimSpace(string(merge))
+	return runQuiet(repoDir, "git", "push", "--force-with-lease", strings.TrimSpace(string(remote)), refspec)
+}
+
+// MergedBranches returns local branches fully merged into the given ref (e.g. origin/main)
+func MergedBranches(repoDir, into string) []string {
+	cmd := command("git", "branch", "--merged", into, "--format=%(refname:short)")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	raw := strings.TrimSpace(string(out))
+	if raw == "" {
+		return nil
+	}
+	return strings.Split(raw, "\n")
+}
+
+// DeleteBranch force-deletes a local branch with output suppressed (callers must check it is merged)
+func DeleteBranch(repoDir, branch string) error {
+	return runQuiet(repoDir, "git", "branch", "-D", branch)
+}
+
+// ResetHard moves the current branch to ref and discards all local changes and
+// commits not in ref, with output suppressed
+func ResetHard(repoDir, ref string) error {
+	return runQuiet(repoDir, "git", "reset", "--hard", ref)
+}
+
 // CheckoutQuiet switches to a branch with output suppressed
 func CheckoutQuiet(repoDir, branch string) error {
 	return runQuiet(repoDir, "git", "checkout", branch)
 }
 
-// GetDefaultBranch attempts to determine the default branch (main or prod)
-func GetDefaultBranch(repoDir string) string {
-	cmd := exec.Command("git", "symbolic-ref", "refs/remotes/origin/HEAD")
+// HasCommit reports whether the given SHA exists in the local object database
+func HasCommit(repoDir, sha string) bool {
+	return runQuiet(repoDir, "git", "cat-file", "-e", sha+"^{commit}") == nil
+}
+
+// CheckoutSHA checks out a specific commit (detached HEAD) with output suppressed
+func CheckoutSHA(repoDir, sha string) error {
+	return runQuiet(repoDir, "git", "checkout", "--detach", sha)
+}
+
+// RemoteBranchExists reports whether <remote>/<branch> is a known ref
+func RemoteBranchExists(repoDir, remote, branch string) bool {
+	return runQuiet(repoDir, "git", "rev-parse", "--verify", "--quiet", "refs/remotes/"+remote+"/"+branch) == nil
+}
+
+// RemoteHeadBranch returns the branch <remote>/HEAD points at, or "" when the
+// remote's HEAD isn't known locally (e.g. never set for a non-origin remote)
+func RemoteHeadBranch(repoDir, remote string) string {
+	cmd := command("git", "symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD")
 	cmd.Dir = repoDir
 	out, err := cmd.Output()
-	if err == nil {
-		ref := strings.TrimSpace(string(out))
-		parts := strings.Split(ref, "/")
-		if len(parts) > 0 {
-			return parts[len(parts)-1]
-		}
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(out)), remote+"/")
+}
+
+// GetDefaultBranch attempts to determine the default branch (main or prod)
+func GetDefaultBranch(repoDir string) string {
+	if b := RemoteHeadBranch(repoDir, "origin"); b != "" {
+		return b
 	}
 
 	for _, branch := range []string{"main", "prod"} {
-		cmd := exec.Command("git", "rev-parse", "--verify", "origin/"+branch)
+		cmd := command("git", "rev-parse", "--verify", "origin/"+branch)
 		cmd.Dir = repoDir
 		if err := cmd.Run(); err == nil {
 			return branch
@@ -1,21 +1,21 @@
 package github
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"os/exec"
+	"math/rand"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/Spark-Rewards/homebrew-spark-cli/internal/aws"
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/smithy-go"
 )
 
-type ssmParameter struct {
-	Name  string `json:"Name"`
-	Value string `json:"Value"`
-}
-
-type ssmResponse struct {
-	Parameters []ssmParameter `json:"Parameters"`
-}
-
 // FetchTokenFromSSM retrieves the GitHub token from AWS SSM Parameter Store
 func FetchTokenFromSSM(profile, env, region string) (string, error) {
 	if region == "" {
@@ -24,34 +24,19 @@ func FetchTokenFromSSM(profile, env, region string) (string, error) {
 
 	paramName := fmt.Sprintf("/app/%s/githubToken", env)
 
-	args := []string{
-		"ssm", "get-parameters",
-		"--names", paramName,
-		"--with-decryption",
-		"--region", region,
-	}
-
-	if profile != "" {
-		args = append(args, "--profile", profile)
+	client, err := newSSMClient(profile, region)
+	if err != nil {
+		return "", err
 	}
 
-	cmd := exec.Command("aws", args...)
-	out, err := cmd.Output()
+	params, err := fetchSSMBatch(client, []string{paramName})
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return "", fmt.Errorf("failed to fetch GitHub token: %s", string(exitErr.Stderr))
-		}
 		return "", fmt.Errorf("failed to fetch GitHub token: %w", err)
 	}
 
-	var resp ssmResponse
-	if err := json.Unmarshal(out, &resp); err != nil {
-		return "", fmt.Errorf("failed to parse SSM response: %w", err)
-	}
-
-	for _, param := range resp.Parameters {
-		if param.Name == paramName {
-			return strings.TrimSpace(param.Value), nil
+	for _, param := range params {
+		if awssdk.ToString(param.Name) == paramName {
+			return strings.TrimSpace(awssdk.ToString(param.Value)), nil
 		}
 	}
 
@@ -61,58 +46,164 @@ func FetchTokenFromSSM(profile, env, region string) (string, error) {
 // maxSSMParamsPerRequest is the AWS GetParameters limit (10 names per call)
 const maxSSMParamsPerRequest = 10
 
+// maxConcurrentSSMRequests bounds how many get-parameters calls run at once
+const maxConcurrentSSMRequests = 4
+
 // FetchMultipleFromSSM retrieves multiple parameters from AWS SSM, batching requests
-// (GetParameters allows at most 10 names per call).
+// (GetParameters allows at most 10 names per call) and fetching batches concurrently.
 func FetchMultipleFromSSM(profile, env, region string, paramSuffixes []string) (map[string]string, error) {
 	if region == "" {
 		region = "us-east-1"
 	}
 
+	client, err := newSSMClient(profile, region)
+	if err != nil {
+		return nil, err
+	}
+
 	prefix := fmt.Sprintf("/app/%s/", env)
 	result := make(map[string]string)
 
+	var batches [][]string
 	for i := 0; i < len(paramSuffixes); i += maxSSMParamsPerRequest {
 		end := i + maxSSMParamsPerRequest
 		if end > len(paramSuffixes) {
 			end = len(paramSuffixes)
 		}
-		batch := paramSuffixes[i:end]
 
 		var paramNames []string
-		for _, suffix := range batch {
-			paramNames = append(paramNames, fmt.Sprintf("/app/%s/%s", env, suffix))
+		for _, suffix := range paramSuffixes[i:end] {
+			paramNames = append(paramNames, prefix+suffix)
 		}
+		batches = append(batches, paramNames)
+	}
 
-		args := []string{
-			"ssm", "get-parameters",
-			"--names",
-		}
-		args = append(args, paramNames...)
-		args = append(args, "--with-decryption", "--region", region)
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	sem := make(chan struct{}, maxConcurrentSSMRequests)
+
+	for _, batch := range batches {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(names []string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			params, err := fetchSSMBatch(client, names)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to fetch parameters: %w", err)
+				}
+				return
+			}
+			for _, param := range params {
+				key := strings.TrimPrefix(awssdk.ToString(param.Name), prefix)
+				result[key] = strings.TrimSpace(awssdk.ToString(param.Value))
+			}
+		}(batch)
+	}
+	wg.Wait()
 
-		if profile != "" {
-			args = append(args, "--profile", profile)
-		}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return result, nil
+}
 
-		cmd := exec.Command("aws", args...)
-		out, err := cmd.Output()
+// ListSSMEnvs returns each environment segment under /app/ (e.g. beta, prod)
+// with how many parameters it holds. Only names are read, never values.
+func ListSSMEnvs(profile, region string) (map[string]int, error) {
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	client, err := newSSMClient(profile, region)
+	if err != nil {
+		return nil, err
+	}
+
+	envs := make(map[string]int)
+	pager := ssm.NewDescribeParametersPaginator(client, &ssm.DescribeParametersInput{
+		ParameterFilters: []ssmtypes.ParameterStringFilter{{
+			Key:    awssdk.String("Path"),
+			Option: awssdk.String("Recursive"),
+			Values: []string{"/app/"},
+		}},
+		MaxResults: awssdk.Int32(50),
+	})
+	for pager.HasMorePages() {
+		page, err := pager.NextPage(context.Background())
 		if err != nil {
-			if exitErr, ok := err.(*exec.ExitError); ok {
-				return nil, fmt.Errorf("failed to fetch parameters: %s", string(exitErr.Stderr))
+			return nil, fmt.Errorf("failed to list parameters under /app/: %w", err)
+		}
+		for _, p := range page.Parameters {
+			rest := strings.TrimPrefix(awssdk.ToString(p.Name), "/app/")
+			if env, _, ok := strings.Cut(rest, "/"); ok && env != "" {
+				envs[env]++
 			}
-			return nil, fmt.Errorf("failed to fetch parameters: %w", err)
 		}
+	}
+	return envs, nil
+}
 
-		var resp ssmResponse
-		if err := json.Unmarshal(out, &resp); err != nil {
-			return nil, fmt.Errorf("failed to parse SSM response: %w", err)
-		}
+// newSSMClient builds an SSM client for the named profile and region
+func newSSMClient(profile, region string) (*ssm.Client, error) {
+	cfg, err := aws.LoadSDKConfig(context.Background(), profile, region)
+	if err != nil {
+		return nil, err
+	}
+	return ssm.NewFromConfig(cfg), nil
+}
+
+// Throttling retry settings for GetParameters (on top of the SDK's own retries)
+const (
+	maxSSMThrottleRetries = 5
+	ssmBackoffBase        = 250 * time.Millisecond
+	ssmBackoffMax         = 8 * time.Second
+)
 
-		for _, param := range resp.Parameters {
-			key := strings.TrimPrefix(param.Name, prefix)
-			result[key] = strings.TrimSpace(param.Value)
+// fetchSSMBatch runs a single GetParameters call for up to 10 names, retrying
+// with exponential backoff and jitter when AWS throttles the request.
+func fetchSSMBatch(client *ssm.Client, paramNames []string) ([]ssmtypes.Parameter, error) {
+	for attempt := 0; ; attempt++ {
+		out, err := client.GetParameters(context.Background(), &ssm.GetParametersInput{
+			Names:          paramNames,
+			WithDecryption: awssdk.Bool(true),
+		})
+		if err == nil {
+			return out.Parameters, nil
 		}
+		if !isThrottlingError(err) || attempt >= maxSSMThrottleRetries {
+			return nil, err
+		}
+		time.Sleep(backoffDelay(attempt))
 	}
+}
 
-	return result, nil
+// isThrottlingError reports whether err is an AWS throttling response
+func isThrottlingError(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "ThrottlingException", "Throttling", "TooManyRequestsException", "RequestLimitExceeded":
+		return true
+	}
+	return false
+}
+
+// backoffDelay returns base*2^attempt (capped) with full jitter
+func backoffDelay(attempt int) time.Duration {
+	d := ssmBackoffBase << attempt
+	if d <= 0 || d > ssmBackoffMax {
+		d = ssmBackoffMax
+	}
+	return time.Duration(rand.Int63n(int64(d))) + ssmBackoffBase/2
 }
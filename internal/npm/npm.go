@@ -1,10 +1,15 @@
 package npm
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"time"
 )
 
 const (
@@ -36,6 +41,29 @@ func DirectLink(consumerDir, pkg, buildDir string) error {
 	return os.Symlink(absBuild, target)
 }
 
+const (
+	linkAttempts   = 3
+	linkRetryDelay = 250 * time.Millisecond
+)
+
+// DirectLinkWithRetry runs DirectLink, retrying transient EEXIST/ENOENT
+// failures (e.g. a concurrent install touching node_modules). Before a retry
+// the conflicting entry is removed.
+func DirectLinkWithRetry(consumerDir, pkg, buildDir string) error {
+	var err error
+	for attempt := 1; attempt <= linkAttempts; attempt++ {
+		if err = DirectLink(consumerDir, pkg, buildDir); err == nil {
+			return nil
+		}
+		if !errors.Is(err, fs.ErrExist) && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		time.Sleep(linkRetryDelay * time.Duration(attempt))
+		os.RemoveAll(filepath.Join(consumerDir, "node_modules", pkg))
+	}
+	return fmt.Errorf("link %s failed after %d attempts: %w", pkg, linkAttempts, err)
+}
+
 // Unlink removes a symlinked package and does NOT reinstall the published
 // version — the next `npm install` (or spark-cli sync) will restore it.
 func Unlink(consumerDir, pkg string) error {
@@ -89,22 +117,28 @@ func IsBuiltForCodegen(modelDir, codegen string) bool {
 // GetPackageName reads the package name from a package.json file
 func GetPackageName(dir string) (string, error) {
 	packageJSON := filepath.Join(dir, "package.json")
-	if _, err := os.Stat(packageJSON); err != nil {
-		return "", fmt.Errorf("package.json not found in %s", dir)
-	}
-
-	cmd := exec.Command("node", "-p", "require('./package.json').name")
-	cmd.Dir = dir
-	out, err := cmd.Output()
+	data, err := os.ReadFile(packageJSON)
 	if err != nil {
-		return "", fmt.Errorf("failed to read package name: %w", err)
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("package.json not found in %s", dir)
+		}
+		return "", fmt.Errorf("failed to read %s: %w", packageJSON, err)
 	}
 
-	name := string(out)
-	if len(name) > 0 && name[len(name)-1] == '\n' {
-		name = name[:len(name)-1]
+	var pkg struct {
+		Name string `json:"name"`
 	}
-	return name, nil
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return "", fmt.Errorf("invalid JSON in %s at byte %d: %w", packageJSON, syntaxErr.Offset, err)
+		}
+		return "", fmt.Errorf("failed to parse %s: %w", packageJSON, err)
+	}
+	if pkg.Name == "" {
+		return "", fmt.Errorf("%s has no \"name\" field", packageJSON)
+	}
+	return pkg.Name, nil
 }
 
 // IsLinked checks if a package is currently npm-linked in the given directory
@@ -117,6 +151,29 @@ func IsLinked(dir, pkg string) bool {
 	return info.Mode()&os.ModeSymlink != 0
 }
 
+// IsLinkedTo checks that node_modules/<pkg> in dir is a symlink that resolves
+// to expectedTarget (e.g. the model's current build dir), not a stale link
+// pointing at some other project's build.
+func IsLinkedTo(dir, pkg, expectedTarget string) bool {
+	if !IsLinked(dir, pkg) {
+		return false
+	}
+	return SameResolvedPath(filepath.Join(dir, "node_modules", pkg), expectedTarget)
+}
+
+// SameResolvedPath reports whether two paths resolve to the same location after following symlinks
+func SameResolvedPath(a, b string) bool {
+	resolvedA, err := filepath.EvalSymlinks(a)
+	if err != nil {
+		return false
+	}
+	resolvedB, err := filepath.EvalSymlinks(b)
+	if err != nil {
+		return false
+	}
+	return resolvedA == resolvedB
+}
+
 // CheckNPM verifies that npm is installed
 func CheckNPM() error {
 	_, err := exec.LookPath("npm")
@@ -125,3 +182,53 @@ func CheckNPM() error {
 	}
 	return nil
 }
+
+// WorkspaceDirs returns the package directories of an npm workspaces
+// monorepo, relative to repoDir and sorted, from the root package.json
+// "workspaces" globs (array form or {"packages": [...]}). A repo without
+// workspaces returns nil.
+func WorkspaceDirs(repoDir string) []string {
+	data, err := os.ReadFile(filepath.Join(repoDir, "package.json"))
+	if err != nil {
+		return nil
+	}
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if json.Unmarshal(data, &pkg) != nil || len(pkg.Workspaces) == 0 {
+		return nil
+	}
+
+	var patterns []string
+	if json.Unmarshal(pkg.Workspaces, &patterns) != nil {
+		var obj struct {
+			Packages []string `json:"packages"`
+		}
+		if json.Unmarshal(pkg.Workspaces, &obj) != nil {
+			return nil
+		}
+		patterns = obj.Packages
+	}
+
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(repoDir, filepath.FromSlash(pattern)))
+		if err != nil {
+			continue
+		}
+		for _, m := range matches {
+			if _, err := os.Stat(filepath.Join(m, "package.json")); err != nil {
+				continue
+			}
+			rel, err := filepath.Rel(repoDir, m)
+			if err != nil || seen[rel] {
+				continue
+			}
+			seen[rel] = true
+			dirs = append(dirs, rel)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
@@ -0,0 +1,86 @@
+package workspace
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// QuoteEnvValue quotes a .env value that dotenv parsers would otherwise mangle
+// (whitespace, #, quotes, JSON). Single quotes are used where possible, as
+// dotenv and shells take them literally; values with a ' or a newline are
+// double-quoted with escapes instead. Plain values are written as-is.
+func QuoteEnvValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n\r#'\"\\$`") {
+		return value
+	}
+	if !strings.ContainsAny(value, "'\n\r") {
+		return "'" + value + "'"
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "$", `\$`, "`", "\\`")
+	return `"` + r.Replace(value) + `"`
+}
+
+// unquoteEnvValue reverses QuoteEnvValue for a value read from .env. Single
+// quotes are taken literally; unquoted values are returned unchanged.
+func unquoteEnvValue(value string) string {
+	if len(value) < 2 {
+		return value
+	}
+	switch {
+	case value[0] == '\'' && value[len(value)-1] == '\'':
+		return value[1 : len(value)-1]
+	case value[0] == '"' && value[len(value)-1] == '"':
+		inner := value[1 : len(value)-1]
+		var b strings.Builder
+		for i := 0; i < len(inner); i++ {
+			if inner[i] != '\\' || i+1 == len(inner) {
+				b.WriteByte(inner[i])
+				continue
+			}
+			i++
+			switch inner[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case '\\', '"', '$', '`':
+				b.WriteByte(inner[i])
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(inner[i])
+			}
+		}
+		return b.String()
+	}
+	return value
+}
+
+// ExpandJSONEnv adds one var per top-level field of each listed key whose value
+// is a JSON object (e.g. APP_CONFIG_VALUES={"API_URL": ...} → API_URL). Fields
+// that aren't valid var names, or that are already set, are left alone;
+// non-string field values are kept as compact JSON.
+func ExpandJSONEnv(env map[string]string, keys []string) {
+	for _, key := range keys {
+		var fields map[string]json.RawMessage
+		if json.Unmarshal([]byte(env[key]), &fields) != nil {
+			continue
+		}
+		for name, raw := range fields {
+			if !envNamePattern.MatchString(name) {
+				continue
+			}
+			if _, ok := env[name]; ok {
+				continue
+			}
+			var s string
+			if json.Unmarshal(raw, &s) == nil {
+				env[name] = s
+			} else {
+				env[name] = string(raw)
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package workspace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// isYAMLManifest reports whether the manifest at path is YAML rather than JSON
+func isYAMLManifest(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// yamlToJSON converts a YAML manifest to JSON so it decodes through the same
+// json tags as workspace.json. Scalars are coerced to strings where the
+// Workspace field is a string, so `PORT: 3000` in env works unquoted.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+	return json.Marshal(coerceToType(raw, reflect.TypeOf(Workspace{})))
+}
+
+// coerceToType walks a decoded YAML value alongside the Go type it will be
+// decoded into, turning non-string scalars into strings where a string is expected.
+func coerceToType(v interface{}, t reflect.Type) interface{} {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := strings.Split(f.Tag.Get("json"), ",")[0]
+			if name == "" {
+				name = f.Name
+			}
+			if fv, ok := m[name]; ok {
+				m[name] = coerceToType(fv, f.Type)
+			}
+		}
+		return m
+	case reflect.Map:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		for k, mv := range m {
+			m[k] = coerceToType(mv, t.Elem())
+		}
+		return m
+	case reflect.Slice:
+		s, ok := v.([]interface{})
+		if !ok {
+			return v
+		}
+		for i := range s {
+			s[i] = coerceToType(s[i], t.Elem())
+		}
+		return s
+	case reflect.String:
+		switch sv := v.(type) {
+		case nil, string:
+			return v
+		case time.Time:
+			return sv.Format(time.RFC3339)
+		default:
+			return fmt.Sprint(sv)
+		}
+	}
+	if ts, ok := v.(time.Time); ok {
+		return ts.Format(time.RFC3339)
+	}
+	return v
+}
+
+// jsonToYAML renders the JSON-marshaled manifest as block-style YAML, carrying
+// over comments from the existing file for keys that are still present.
+func jsonToYAML(data, existing []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	resetStyle(&doc)
+
+	var old yaml.Node
+	if len(existing) > 0 && yaml.Unmarshal(existing, &old) == nil {
+		copyComments(&doc, &old)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resetStyle clears the flow/quoted styles JSON input decodes with, so the
+// encoder writes plain block YAML (quoting only where needed)
+func resetStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		resetStyle(c)
+	}
+}
+
+// copyComments copies head/line/foot comments from src onto matching nodes in
+// dst: mapping entries by key, sequence entries by scalar value or position.
+func copyComments(dst, src *yaml.Node) {
+	dst.HeadComment, dst.LineComment, dst.FootComment = src.HeadComment, src.LineComment, src.FootComment
+	switch {
+	case dst.Kind == yaml.DocumentNode && src.Kind == yaml.DocumentNode:
+		if len(dst.Content) > 0 && len(src.Content) > 0 {
+			copyComments(dst.Content[0], src.Content[0])
+		}
+	case dst.Kind == yaml.MappingNode && src.Kind == yaml.MappingNode:
+		entries := make(map[string][2]*yaml.Node)
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			entries[src.Content[i].Value] = [2]*yaml.Node{src.Content[i], src.Content[i+1]}
+		}
+		for i := 0; i+1 < len(dst.Content); i += 2 {
+			if e, ok := entries[dst.Content[i].Value]; ok {
+				copyComments(dst.Content[i], e[0])
+				copyComments(dst.Content[i+1], e[1])
+			}
+		}
+	case dst.Kind == yaml.SequenceNode && src.Kind == yaml.SequenceNode:
+		byValue := make(map[string]*yaml.Node)
+		for _, c := range src.Content {
+			if c.Kind == yaml.ScalarNode {
+				byValue[c.Value] = c
+			}
+		}
+		for i, c := range dst.Content {
+			if c.Kind == yaml.ScalarNode {
+				if s, ok := byValue[c.Value]; ok {
+					copyComments(c, s)
+				}
+			} else if i < len(src.Content) {
+				copyComments(c, src.Content[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package workspace
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SSMCacheFile holds recently fetched SSM parameters, one entry per
+// profile/region/environment, so switching back to an env skips SSM.
+const SSMCacheFile = "ssm-cache.json"
+
+type ssmCacheEntry struct {
+	FetchedAt time.Time         `json:"fetched_at"`
+	Params    map[string]string `json:"params"`
+}
+
+func ssmCachePath(workspacePath string) string {
+	return filepath.Join(SparkDir(workspacePath), SSMCacheFile)
+}
+
+func ssmCacheKey(profile, region, env string) string {
+	return profile + "|" + region + "|" + env
+}
+
+func loadSSMCache(workspacePath string) map[string]ssmCacheEntry {
+	cache := make(map[string]ssmCacheEntry)
+	data, err := os.ReadFile(ssmCachePath(workspacePath))
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return make(map[string]ssmCacheEntry)
+	}
+	return cache
+}
+
+// CachedSSMParams returns the parameters cached for this profile/region/env
+// and their age, if fetched within ttl
+func CachedSSMParams(workspacePath, profile, region, env string, ttl time.Duration) (map[string]string, time.Duration, bool) {
+	if ttl <= 0 {
+		return nil, 0, false
+	}
+	entry, ok := loadSSMCache(workspacePath)[ssmCacheKey(profile, region, env)]
+	if !ok || len(entry.Params) == 0 {
+		return nil, 0, false
+	}
+	age := time.Since(entry.FetchedAt)
+	if age < 0 || age > ttl {
+		return nil, 0, false
+	}
+	return entry.Params, age, true
+}
+
+// CacheSSMParams stores freshly fetched parameters for this profile/region/env,
+// leaving other environments' entries in place. The file holds secrets, so it
+// is written owner-only.
+func CacheSSMParams(workspacePath, profile, region, env string, params map[string]string) error {
+	cache := loadSSMCache(workspacePath)
+	cache[ssmCacheKey(profile, region, env)] = ssmCacheEntry{FetchedAt: time.Now(), Params: params}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := ssmCachePath(workspacePath)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps an existing file's mode, so tighten it explicitly
+	return os.Chmod(path, 0600)
+}
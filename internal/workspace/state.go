@@ -0,0 +1,79 @@
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const StateFile = "state.json"
+
+// State is machine-managed workspace state (not user config), stored in .spk/state.json
+type State struct {
+	LastBuiltSHA    map[string]string `json:"last_built_sha,omitempty"`   // repo name → HEAD at last successful build
+	DefaultBranches map[string]string `json:"default_branches,omitempty"` // repo path → detected origin default branch
+	SSMEnvKeys      []string          `json:"ssm_env_keys,omitempty"`     // .env keys written by the last SSM refresh
+}
+
+// StatePath returns the full path to .spk/state.json
+func StatePath(workspacePath string) string {
+	return filepath.Join(SparkDir(workspacePath), StateFile)
+}
+
+// LoadState reads workspace state; a missing file returns empty state
+func LoadState(workspacePath string) (*State, error) {
+	data, err := os.ReadFile(StatePath(workspacePath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &State{}, nil
+		}
+		return nil, fmt.Errorf("failed to read workspace state: %w", err)
+	}
+
+	var st State
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace state: %w", err)
+	}
+	return &st, nil
+}
+
+// SaveState writes workspace state to disk
+func SaveState(workspacePath string, st *State) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal workspace state: %w", err)
+	}
+	return os.WriteFile(StatePath(workspacePath), data, 0644)
+}
+
+// RecordBuild stores the SHA a repo was successfully built at
+func RecordBuild(workspacePath, repoName, sha string) error {
+	st, err := LoadState(workspacePath)
+	if err != nil {
+		return err
+	}
+	if st.LastBuiltSHA == nil {
+		st.LastBuiltSHA = make(map[string]string)
+	}
+	st.LastBuiltSHA[repoName] = sha
+	return SaveState(workspacePath, st)
+}
+
+// RenameRepoState moves a renamed repo's recorded build SHA and, when its
+// directory moved, its cached default branch
+func RenameRepoState(workspacePath, oldName, newName, oldPath, newPath string) error {
+	st, err := LoadState(workspacePath)
+	if err != nil {
+		return err
+	}
+	if sha, ok := st.LastBuiltSHA[oldName]; ok {
+		delete(st.LastBuiltSHA, oldName)
+		st.LastBuiltSHA[newName] = sha
+	}
+	if branch, ok := st.DefaultBranches[oldPath]; ok && newPath != oldPath {
+		delete(st.DefaultBranches, oldPath)
+		st.DefaultBranches[newPath] = branch
+	}
+	return SaveState(workspacePath, st)
+}
@@ -4,7 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/Spark-Rewards/homebrew-spark-cli/internal/config"
@@ -12,25 +16,129 @@ import (
 
 const ManifestFile = "workspace.json"
 
+// ManifestYAMLFiles are accepted in place of workspace.json, so the manifest can carry comments
+var ManifestYAMLFiles = []string{"workspace.yaml", "workspace.yml"}
+
 type RepoDef struct {
-	Remote        string   `json:"remote"`
-	Path          string   `json:"path"`
-	BuildCommand  string   `json:"build_command,omitempty"`
-	TestCommand   string   `json:"test_command,omitempty"`
-	Dependencies  []string `json:"dependencies,omitempty"`
-	DefaultBranch string   `json:"default_branch,omitempty"`
-	ModelFor      string   `json:"model_for,omitempty"`
+	Remote        string            `json:"remote"`
+	Path          string            `json:"path"`
+	BuildCommand  string            `json:"build_command,omitempty"`
+	TestCommand   string            `json:"test_command,omitempty"`
+	Dependencies  []string          `json:"dependencies,omitempty"`
+	DefaultBranch string            `json:"default_branch,omitempty"`
+	ModelFor      string            `json:"model_for,omitempty"`
+	Consumers     []string          `json:"consumers,omitempty"` // further repos consuming this model's SDK
+	SkipSync      bool              `json:"skip_sync,omitempty"`
+	SyncStrategy  string            `json:"sync_strategy,omitempty"`   // "rebase" (default), "merge" or "pull"
+	SyncRemote    string            `json:"sync_remote,omitempty"`     // remote to rebase onto, e.g. "upstream" for forks (default origin)
+	Env           map[string]string `json:"env,omitempty"`             // overrides workspace env for this repo only
+	InstallArgs   []string          `json:"install_args,omitempty"`    // extra npm install flags, e.g. --legacy-peer-deps
+	EnvKeys       []string          `json:"env_keys,omitempty"`        // keys (or globs like NEXT_PUBLIC_*; !KEY excludes) written to the repo's scoped .env
+	ExpandJSONEnv []string          `json:"expand_json_env,omitempty"` // JSON-object env values also exposed as one var per field
+}
+
+// UpstreamRemote returns the remote sync rebases/merges onto (sync_remote, default origin)
+func (r RepoDef) UpstreamRemote() string {
+	if r.SyncRemote != "" {
+		return r.SyncRemote
+	}
+	return "origin"
+}
+
+// InstallCommand returns the npm install command for this repo, including any install_args
+func (r RepoDef) InstallCommand() string {
+	return strings.TrimSpace("npm install " + strings.Join(r.InstallArgs, " "))
+}
+
+// ModelConsumers returns every repo consuming this model's SDK: model_for
+// followed by any extra consumers, without duplicates.
+func (r RepoDef) ModelConsumers() []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, c := range append([]string{r.ModelFor}, r.Consumers...) {
+		if c != "" && !seen[c] {
+			seen[c] = true
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// SkipSyncMarker is a file that, when present in a repo root, excludes it from 'workspace sync'
+const SkipSyncMarker = ".spk-skip"
+
+// SkipsSync reports whether a repo is excluded from full-workspace sync, via
+// skip_sync in workspace.json or a .spk-skip marker file in the repo.
+func SkipsSync(repo RepoDef, repoDir string) bool {
+	if repo.SkipSync {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(repoDir, SkipSyncMarker))
+	return err == nil
 }
 
 type Workspace struct {
-	Name          string             `json:"name"`
-	CreatedAt     string             `json:"created_at"`
-	AWSProfile    string             `json:"aws_profile,omitempty"`
-	AWSRegion     string             `json:"aws_region,omitempty"`
-	Repos         map[string]RepoDef `json:"repos"`
-	Env           map[string]string  `json:"env,omitempty"`
-	DefaultBranch string             `json:"default_branch,omitempty"`
-	SSMEnvPath    string             `json:"ssm_env_path,omitempty"`
+	Name           string              `json:"name"`
+	CreatedAt      string              `json:"created_at"`
+	AWSProfile     string              `json:"aws_profile,omitempty"`
+	AWSRegion      string              `json:"aws_region,omitempty"`
+	Repos          map[string]RepoDef  `json:"repos"`
+	Env            map[string]string   `json:"env,omitempty"`
+	DefaultBranch  string              `json:"default_branch,omitempty"`
+	SSMEnvPath     string              `json:"ssm_env_path,omitempty"`
+	AutoRefreshEnv bool                `json:"auto_refresh_env,omitempty"` // sync refreshes .env from ssm_env_path without --env
+	VSCode         *VSCodeConfig       `json:"vscode,omitempty"`
+	Hooks          map[string]string   `json:"hooks,omitempty"`           // git hook name → script path (relative to workspace root)
+	Groups         map[string][]string `json:"groups,omitempty"`          // named repo sets, e.g. "business": [BusinessAPI, BusinessModel]
+	RemoteProtocol string              `json:"remote_protocol,omitempty"` // "ssh" (default) or "https" for new clones
+	RemoteToken    bool                `json:"remote_token,omitempty"`    // with https, authenticate clones with GITHUB_TOKEN
+	ConfigRepo     string              `json:"config_repo,omitempty"`     // org/repo (or URL) of the git checkout holding this manifest; pulled by sync --update-config
+}
+
+// GroupRepos returns the repos in a named group, checking each is defined in the workspace
+func GroupRepos(ws *Workspace, group string) ([]string, error) {
+	members, ok := ws.Groups[group]
+	if !ok {
+		var known []string
+		for name := range ws.Groups {
+			known = append(known, name)
+		}
+		sort.Strings(known)
+		if len(known) == 0 {
+			return nil, fmt.Errorf("group '%s' not found — no groups defined in workspace.json", group)
+		}
+		return nil, fmt.Errorf("group '%s' not found (groups: %s)", group, strings.Join(known, ", "))
+	}
+
+	var unknown []string
+	for _, name := range members {
+		if _, ok := ws.Repos[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("group '%s' lists repos not in workspace: %s", group, strings.Join(unknown, ", "))
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("group '%s' is empty", group)
+	}
+	return members, nil
+}
+
+// VSCodeConfig customizes the generated .code-workspace file
+type VSCodeConfig struct {
+	Disabled    bool                   `json:"disabled,omitempty"`     // skip generating the file entirely
+	Extensions  []string               `json:"extensions,omitempty"`   // replaces the default recommendations
+	Settings    map[string]interface{} `json:"settings,omitempty"`     // merged over the generated settings
+	TerminalEnv []string               `json:"terminal_env,omitempty"` // .env keys (or globs) inlined as terminal env; none by default, as the file is often shared
+}
+
+// DefaultVSCodeExtensions are recommended in the generated .code-workspace file
+var DefaultVSCodeExtensions = []string{
+	"dbaeumer.vscode-eslint",
+	"esbenp.prettier-vscode",
+	"smithy.smithy-vscode-extension",
+	"amazonwebservices.aws-toolkit-vscode",
 }
 
 // SparkDir returns the .spark directory path within a workspace
@@ -38,9 +146,20 @@ func SparkDir(workspacePath string) string {
 	return filepath.Join(workspacePath, config.SparkDir)
 }
 
-// ManifestPath returns the full path to workspace.json
+// ManifestPath returns the full path to the workspace manifest: workspace.json,
+// or workspace.yaml/.yml when that is the file present
 func ManifestPath(workspacePath string) string {
-	return filepath.Join(SparkDir(workspacePath), ManifestFile)
+	jsonPath := filepath.Join(SparkDir(workspacePath), ManifestFile)
+	if _, err := os.Stat(jsonPath); err == nil {
+		return jsonPath
+	}
+	for _, name := range ManifestYAMLFiles {
+		path := filepath.Join(SparkDir(workspacePath), name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return jsonPath
 }
 
 // Create initializes a new workspace at the given path
@@ -82,6 +201,12 @@ func Load(workspacePath string) (*Workspace, error) {
 		return nil, fmt.Errorf("failed to read workspace manifest: %w", err)
 	}
 
+	if isYAMLManifest(path) {
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, fmt.Errorf("failed to parse workspace manifest %s: %w", path, err)
+		}
+	}
+
 	var ws Workspace
 	if err := json.Unmarshal(data, &ws); err != nil {
 		return nil, fmt.Errorf("failed to parse workspace manifest: %w", err)
@@ -96,20 +221,60 @@ func Save(workspacePath string, ws *Workspace) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal workspace manifest: %w", err)
 	}
+	if isYAMLManifest(path) {
+		existing, _ := os.ReadFile(path)
+		if data, err = jsonToYAML(data, existing); err != nil {
+			return fmt.Errorf("failed to marshal workspace manifest: %w", err)
+		}
+	}
 	return os.WriteFile(path, data, 0644)
 }
 
+// rootOverride, when set, is returned by Find instead of searching from the cwd
+var rootOverride string
+
+// SetRoot makes Find return the workspace at path (e.g. from --workspace)
+// instead of walking up from the current directory.
+func SetRoot(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("invalid workspace path: %w", err)
+	}
+	if _, err := os.Stat(ManifestPath(abs)); err != nil {
+		return fmt.Errorf("no spark-cli workspace at %s (no .spk/workspace.json or workspace.yaml found)", abs)
+	}
+	rootOverride = abs
+	return nil
+}
+
 // Find walks up from the current directory to find a workspace root
 func Find() (string, error) {
+	if rootOverride != "" {
+		return rootOverride, nil
+	}
+	roots, err := FindAll()
+	if err != nil {
+		return "", err
+	}
+	if len(roots) == 0 {
+		return "", fmt.Errorf("not inside a spark-cli workspace (no .spk/workspace.json or workspace.yaml found)")
+	}
+	return roots[0], nil
+}
+
+// FindAll returns every workspace root between the current directory and the
+// filesystem root, innermost first. More than one means nested workspaces.
+func FindAll() ([]string, error) {
 	dir, err := os.Getwd()
 	if err != nil {
-		return "", fmt.Errorf("failed to get current directory: %w", err)
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
 	}
 
+	var roots []string
 	for {
 		manifest := ManifestPath(dir)
 		if _, err := os.Stat(manifest); err == nil {
-			return dir, nil
+			roots = append(roots, dir)
 		}
 
 		parent := filepath.Dir(dir)
@@ -118,8 +283,7 @@ func Find() (string, error) {
 		}
 		dir = parent
 	}
-
-	return "", fmt.Errorf("not inside a spark-cli workspace (no .spk/workspace.json found)")
+	return roots, nil
 }
 
 // AddRepo registers a repo in the workspace manifest
@@ -148,6 +312,50 @@ func RemoveRepo(workspacePath, name string) error {
 	return Save(workspacePath, ws)
 }
 
+// RenameRepo changes a repo's key in the manifest and rewrites every reference
+// to it (dependencies, model_for, consumers, groups). A non-empty newPath also
+// updates the repo's path.
+func RenameRepo(workspacePath, oldName, newName, newPath string) error {
+	ws, err := Load(workspacePath)
+	if err != nil {
+		return err
+	}
+
+	repo, ok := ws.Repos[oldName]
+	if !ok {
+		return fmt.Errorf("repo '%s' not found in workspace", oldName)
+	}
+	if _, ok := ws.Repos[newName]; ok {
+		return fmt.Errorf("repo '%s' already exists in workspace", newName)
+	}
+	if newPath != "" {
+		repo.Path = newPath
+	}
+	delete(ws.Repos, oldName)
+	ws.Repos[newName] = repo
+
+	rename := func(names []string) {
+		for i, n := range names {
+			if n == oldName {
+				names[i] = newName
+			}
+		}
+	}
+	for name, r := range ws.Repos {
+		rename(r.Dependencies)
+		rename(r.Consumers)
+		if r.ModelFor == oldName {
+			r.ModelFor = newName
+		}
+		ws.Repos[name] = r
+	}
+	for _, members := range ws.Groups {
+		rename(members)
+	}
+
+	return Save(workspacePath, ws)
+}
+
 // VSCodeWorkspacePath returns the path to the .code-workspace file
 func VSCodeWorkspacePath(workspacePath string) string {
 	ws, err := Load(workspacePath)
@@ -157,26 +365,72 @@ func VSCodeWorkspacePath(workspacePath string) string {
 	return filepath.Join(workspacePath, ws.Name+".code-workspace")
 }
 
-// GenerateVSCodeWorkspace creates/updates the .code-workspace file
+// GenerateVSCodeWorkspace creates/updates the .code-workspace file (no-op when disabled in config)
 func GenerateVSCodeWorkspace(workspacePath string) error {
 	ws, err := Load(workspacePath)
 	if err != nil {
 		return err
 	}
+	if ws.VSCode != nil && ws.VSCode.Disabled {
+		return nil
+	}
 
 	type folder struct {
 		Path string `json:"path"`
 	}
+	type extensions struct {
+		Recommendations []string `json:"recommendations"`
+	}
 	type vscodeWorkspace struct {
-		Folders []folder `json:"folders"`
+		Folders    []folder               `json:"folders"`
+		Settings   map[string]interface{} `json:"settings,omitempty"`
+		Extensions extensions             `json:"extensions"`
 	}
 
+	names := make([]string, 0, len(ws.Repos))
+	for name := range ws.Repos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
 	var folders []folder
-	for _, repo := range ws.Repos {
+	var nodeRoots []string
+	for _, name := range names {
+		repo := ws.Repos[name]
 		folders = append(folders, folder{Path: repo.Path})
+		if _, err := os.Stat(filepath.Join(workspacePath, repo.Path, "package.json")); err == nil {
+			nodeRoots = append(nodeRoots, repo.Path)
+		}
 	}
 
-	vscWs := vscodeWorkspace{Folders: folders}
+	settings := make(map[string]interface{})
+	if ws.VSCode != nil && len(ws.VSCode.TerminalEnv) > 0 {
+		dotEnv, _ := ReadGlobalEnv(workspacePath)
+		if env, _ := ScopeEnv(dotEnv, ws.VSCode.TerminalEnv); len(env) > 0 {
+			settings["terminal.integrated.env.osx"] = env
+			settings["terminal.integrated.env.linux"] = env
+		}
+	}
+	if len(nodeRoots) > 0 {
+		settings["eslint.workingDirectories"] = nodeRoots
+		settings["typescript.tsdk"] = "node_modules/typescript/lib"
+	}
+
+	recommended := DefaultVSCodeExtensions
+	if ws.VSCode != nil {
+		if len(ws.VSCode.Extensions) > 0 {
+			recommended = ws.VSCode.Extensions
+		}
+		for k, v := range ws.VSCode.Settings {
+			settings[k] = v
+		}
+	}
+
+	vscWs := vscodeWorkspace{
+		Folders:    folders,
+		Settings:   settings,
+		Extensions: extensions{Recommendations: recommended},
+	}
 
 	data, err := json.MarshalIndent(vscWs, "", "\t")
 	if err != nil {
@@ -204,10 +458,43 @@ func WriteGlobalEnv(workspacePath string, vars map[string]string) error {
 	for k, v := range vars {
 		existing[k] = v
 	}
+	return writeEnvFile(envPath, existing)
+}
 
+// WriteManagedEnv writes SSM-derived vars to the workspace .env, replacing the
+// set written by the previous refresh (recorded in state.json): keys that
+// refresh wrote and vars no longer has are removed. Other keys are kept.
+func WriteManagedEnv(workspacePath string, vars map[string]string) error {
+	st, err := LoadState(workspacePath)
+	if err != nil {
+		return err
+	}
+	existing, _ := ReadGlobalEnv(workspacePath)
+	if existing == nil {
+		existing = make(map[string]string)
+	}
+	for _, k := range st.SSMEnvKeys {
+		if _, ok := vars[k]; !ok {
+			delete(existing, k)
+		}
+	}
+
+	st.SSMEnvKeys = st.SSMEnvKeys[:0]
+	for k, v := range vars {
+		existing[k] = v
+		st.SSMEnvKeys = append(st.SSMEnvKeys, k)
+	}
+	sort.Strings(st.SSMEnvKeys)
+	if err := writeEnvFile(GlobalEnvPath(workspacePath), existing); err != nil {
+		return err
+	}
+	return SaveState(workspacePath, st)
+}
+
+func writeEnvFile(envPath string, vars map[string]string) error {
 	var lines []string
-	for k, v := range existing {
-		lines = append(lines, fmt.Sprintf("%s=%s", k, v))
+	for k, v := range vars {
+		lines = append(lines, fmt.Sprintf("%s=%s", k, QuoteEnvValue(v)))
 	}
 
 	content := ""
@@ -218,6 +505,121 @@ func WriteGlobalEnv(workspacePath string, vars map[string]string) error {
 	return os.WriteFile(envPath, []byte(content), 0644)
 }
 
+// ScopedEnvHeader marks a repo .env generated from env_keys
+const ScopedEnvHeader = "# Generated by spark-cli from env_keys in workspace.json — do not edit"
+
+// ScopeEnv filters env down to the env_keys patterns: exact keys, globs such as
+// "NEXT_PUBLIC_*", and "!"-prefixed keys or globs that exclude (exclusions win).
+// Exact keys absent from env are returned as missing.
+func ScopeEnv(env map[string]string, patterns []string) (map[string]string, []string) {
+	var allow, deny []string
+	for _, p := range patterns {
+		if strings.HasPrefix(p, "!") {
+			deny = append(deny, p[1:])
+		} else {
+			allow = append(allow, p)
+		}
+	}
+	matchesAny := func(key string, globs []string) bool {
+		for _, g := range globs {
+			if ok, _ := path.Match(g, key); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	scoped := make(map[string]string)
+	for k, v := range env {
+		if matchesAny(k, allow) && !matchesAny(k, deny) {
+			scoped[k] = v
+		}
+	}
+	var missing []string
+	for _, p := range allow {
+		if _, ok := env[p]; !ok && !strings.ContainsAny(p, "*?[") {
+			missing = append(missing, p)
+		}
+	}
+	return scoped, missing
+}
+
+// WriteScopedEnv writes vars, sorted, to repoDir/.env. A symlink there (e.g.
+// to the workspace .env) is replaced rather than written through; an existing
+// file not starting with ScopedEnvHeader is hand-written and is only
+// overwritten with force.
+func WriteScopedEnv(repoDir string, vars map[string]string, force bool) error {
+	envPath := filepath.Join(repoDir, ".env")
+	if info, err := os.Lstat(envPath); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		if err := os.Remove(envPath); err != nil {
+			return err
+		}
+	} else if err == nil && !force {
+		data, err := os.ReadFile(envPath)
+		if err != nil {
+			return err
+		}
+		first, _, _ := strings.Cut(string(data), "\n")
+		if strings.TrimSpace(first) != ScopedEnvHeader {
+			return fmt.Errorf(".env exists and wasn't generated by spark-cli — move it aside or run 'spark-cli workspace env scope --force' to replace it")
+		}
+	}
+
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	content := ScopedEnvHeader + "\n"
+	for _, k := range keys {
+		content += fmt.Sprintf("%s=%s\n", k, QuoteEnvValue(vars[k]))
+	}
+	return os.WriteFile(envPath, []byte(content), 0600)
+}
+
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Interpolate replaces ${KEY} references in value with entries from vars.
+// Unknown references are left untouched.
+func Interpolate(value string, vars map[string]string) string {
+	return envRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+		key := ref[2 : len(ref)-1]
+		if v, ok := vars[key]; ok {
+			return v
+		}
+		return ref
+	})
+}
+
+// OverlayEnv copies overrides onto base, expanding ${KEY} references in the
+// override values against base and the other (unexpanded) overrides.
+func OverlayEnv(base, overrides map[string]string) {
+	lookup := make(map[string]string, len(base)+len(overrides))
+	for k, v := range base {
+		lookup[k] = v
+	}
+	for k, v := range overrides {
+		lookup[k] = v
+	}
+	for k, v := range overrides {
+		base[k] = Interpolate(v, lookup)
+	}
+}
+
+// RepoEnv returns a copy of the workspace env with the repo's own env
+// overrides layered on top (.env < workspace.json env < repo env), plus the
+// fields of any expand_json_env values.
+func RepoEnv(wsEnv map[string]string, repo RepoDef) map[string]string {
+	env := make(map[string]string, len(wsEnv)+len(repo.Env))
+	for k, v := range wsEnv {
+		env[k] = v
+	}
+	OverlayEnv(env, repo.Env)
+	ExpandJSONEnv(env, repo.ExpandJSONEnv)
+	return env
+}
+
 // ReadGlobalEnv reads the workspace's global .env file into a map
 func ReadGlobalEnv(workspacePath string) (map[string]string, error) {
 	envPath := GlobalEnvPath(workspacePath)
@@ -245,7 +647,7 @@ func ReadGlobalEnv(workspacePath string) (map[string]string, error) {
 		}
 
 		key := line[:idx]
-		value := line[idx+1:]
+		value := unquoteEnvValue(line[idx+1:])
 		result[key] = value
 	}
 